@@ -0,0 +1,335 @@
+// Package merrcheck defines an analyzer that reports errors which reach
+// clients as a generic "Internal server error" because they are not
+// merr.PublicErr values, and suspicious merr constructor calls.
+//
+// It reports:
+//   - errors.New, fmt.Errorf without %w and concrete non-public errors
+//     passed to (*gin.Context).Error, (*gin.Context).AbortWithError or
+//     merrmid.AbortWithError;
+//   - the same errors returned from gRPC service methods, i.e. methods of
+//     types embedding a generated Unimplemented...Server;
+//   - merr constructors called with an empty public message;
+//   - merr constructors called with a constant code that is neither built
+//     in nor registered with merr.Register or merr.MustRegister in the
+//     package or one of its dependencies.
+package merrcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	merrPath    = "github.com/mandacode-com/merr"
+	merrmidPath = "github.com/mandacode-com/merr/middleware"
+	ginPath     = "github.com/gin-gonic/gin"
+)
+
+// Analyzer reports raw errors escaping gin handlers and gRPC methods.
+var Analyzer = &analysis.Analyzer{
+	Name:      "merrcheck",
+	Doc:       "report non-public errors escaping gin handlers and gRPC methods, and misused merr constructors",
+	URL:       "https://pkg.go.dev/github.com/mandacode-com/merr/analysis/merrcheck",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(registeredCodes)},
+	Run:       run,
+}
+
+// registeredCodes is a package fact listing the codes a package registers.
+type registeredCodes struct {
+	Codes []string
+}
+
+func (*registeredCodes) AFact() {}
+
+func (f *registeredCodes) String() string {
+	return "registered(" + strings.Join(f.Codes, ", ") + ")"
+}
+
+// constructor describes the arguments of a function creating a public error.
+type constructor struct {
+	code   int
+	public int
+}
+
+// constructors lists the functions creating public errors, by package and name.
+var constructors = map[string]map[string]constructor{
+	merrPath: {
+		"New":             {code: 0, public: 1},
+		"Newf":            {code: 0, public: 1},
+		"NewWithoutStack": {code: 0, public: 1},
+		"WrapCode":        {code: 1, public: 2},
+	},
+	merrmidPath: {
+		"AbortWithPublicError": {code: 1, public: 2},
+		"NewPublicError":       {code: 0, public: 1},
+	},
+}
+
+type checker struct {
+	pass      *analysis.Pass
+	publicErr *types.Interface
+	errCode   types.Type
+	known     map[string]bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	c := &checker{pass: pass}
+	if merr := findPackage(pass.Pkg, merrPath); merr != nil {
+		if obj, ok := merr.Scope().Lookup("PublicErr").(*types.TypeName); ok {
+			c.publicErr, _ = obj.Type().Underlying().(*types.Interface)
+		}
+		if obj, ok := merr.Scope().Lookup("ErrCode").(*types.TypeName); ok {
+			c.errCode = obj.Type()
+		}
+	}
+
+	c.collectCodes(inspect)
+
+	nodes := []ast.Node{(*ast.CallExpr)(nil), (*ast.FuncDecl)(nil)}
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			c.checkCall(n)
+		case *ast.FuncDecl:
+			c.checkGRPCMethod(n)
+		}
+	})
+	return nil, nil
+}
+
+// collectCodes gathers the built-in codes, the codes registered by
+// dependencies and those registered by this package, exporting the latter.
+func (c *checker) collectCodes(inspect *inspector.Inspector) {
+	c.known = make(map[string]bool)
+	if c.errCode == nil {
+		return
+	}
+
+	merr := findPackage(c.pass.Pkg, merrPath)
+	for _, name := range merr.Scope().Names() {
+		if obj, ok := merr.Scope().Lookup(name).(*types.Const); ok && types.Identical(obj.Type(), c.errCode) {
+			c.known[constant.StringVal(obj.Val())] = true
+		}
+	}
+	for _, fact := range c.pass.AllPackageFacts() {
+		if codes, ok := fact.Fact.(*registeredCodes); ok {
+			for _, code := range codes.Codes {
+				c.known[code] = true
+			}
+		}
+	}
+
+	var local []string
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		if !isFunc(c.pass, call, merrPath, "Register", "MustRegister") || len(call.Args) == 0 {
+			return
+		}
+		if code, ok := c.constantCode(call.Args[0]); ok && !c.known[code] {
+			c.known[code] = true
+			local = append(local, code)
+		}
+	})
+	if len(local) > 0 {
+		sort.Strings(local)
+		c.pass.ExportPackageFact(&registeredCodes{Codes: local})
+	}
+}
+
+// checkCall reports raw errors handed to gin and misused merr constructors.
+func (c *checker) checkCall(call *ast.CallExpr) {
+	switch {
+	case isMethod(c.pass, call, ginPath, "Context", "Error") && len(call.Args) == 1:
+		c.checkEscaping(call.Args[0], "(*gin.Context).Error")
+	case isMethod(c.pass, call, ginPath, "Context", "AbortWithError") && len(call.Args) == 2:
+		c.checkEscaping(call.Args[1], "(*gin.Context).AbortWithError")
+	case isFunc(c.pass, call, merrmidPath, "AbortWithError") && len(call.Args) == 2:
+		c.checkEscaping(call.Args[1], "merrmid.AbortWithError")
+	}
+
+	fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return
+	}
+	ctor, ok := constructors[fn.Pkg().Path()][fn.Name()]
+	if !ok || fn.Type().(*types.Signature).Recv() != nil || len(call.Args) <= ctor.public {
+		return
+	}
+
+	public := c.pass.TypesInfo.Types[call.Args[ctor.public]]
+	if public.Value != nil && public.Value.Kind() == constant.String && strings.TrimSpace(constant.StringVal(public.Value)) == "" {
+		c.pass.Reportf(call.Args[ctor.public].Pos(), "%s.%s called with an empty public message", fn.Pkg().Name(), fn.Name())
+	}
+	if code, ok := c.constantCode(call.Args[ctor.code]); ok && !c.known[code] {
+		c.pass.Reportf(call.Args[ctor.code].Pos(), "%s.%s called with unregistered code %q; register it with merr.Register", fn.Pkg().Name(), fn.Name(), code)
+	}
+}
+
+// checkGRPCMethod reports raw errors returned by gRPC service methods.
+func (c *checker) checkGRPCMethod(decl *ast.FuncDecl) {
+	if decl.Recv == nil || decl.Body == nil || !ast.IsExported(decl.Name.Name) {
+		return
+	}
+	fn, ok := c.pass.TypesInfo.Defs[decl.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	results := sig.Results()
+	if results.Len() == 0 || !isErrorType(results.At(results.Len()-1).Type()) || !isGRPCService(sig.Recv().Type()) {
+		return
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) == results.Len() {
+				c.checkEscaping(n.Results[len(n.Results)-1], "gRPC method "+decl.Name.Name)
+			}
+		}
+		return true
+	})
+}
+
+// checkEscaping reports expr if it is known not to be a merr.PublicErr.
+func (c *checker) checkEscaping(expr ast.Expr, dest string) {
+	if origin, ok := c.rawError(expr); ok {
+		c.pass.Reportf(expr.Pos(), "non-public error from %s reaches %s; clients will only see a generic internal server error, use merr.New", origin, dest)
+	}
+}
+
+// rawError reports whether expr is an error that cannot be a merr.PublicErr,
+// describing where it comes from.
+func (c *checker) rawError(expr ast.Expr) (string, bool) {
+	expr = ast.Unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok {
+		switch {
+		case isFunc(c.pass, call, "errors", "New"):
+			return "errors.New", true
+		case isFunc(c.pass, call, "fmt", "Errorf") && len(call.Args) > 0:
+			format := c.pass.TypesInfo.Types[call.Args[0]].Value
+			if format != nil && format.Kind() == constant.String && !strings.Contains(constant.StringVal(format), "%w") {
+				return "fmt.Errorf", true
+			}
+			return "", false
+		}
+	}
+
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	if !ok || tv.IsNil() || c.publicErr == nil || types.IsInterface(tv.Type) {
+		return "", false
+	}
+	if types.Implements(tv.Type, c.publicErr) {
+		return "", false
+	}
+	return types.TypeString(tv.Type, types.RelativeTo(c.pass.Pkg)), true
+}
+
+// constantCode returns the value of expr if it is a constant merr.ErrCode.
+func (c *checker) constantCode(expr ast.Expr) (string, bool) {
+	tv, ok := c.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isGRPCService reports whether t embeds a generated Unimplemented...Server type.
+func isGRPCService(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		ft := f.Type()
+		if ptr, ok := ft.(*types.Pointer); ok {
+			ft = ptr.Elem()
+		}
+		if named, ok := ft.(*types.Named); ok {
+			name := named.Obj().Name()
+			if strings.HasPrefix(name, "Unimplemented") && strings.HasSuffix(name, "Server") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// isFunc reports whether call calls one of the named package-level functions.
+func isFunc(pass *analysis.Pass, call *ast.CallExpr, pkgPath string, names ...string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath || fn.Type().(*types.Signature).Recv() != nil {
+		return false
+	}
+	for _, name := range names {
+		if fn.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isMethod reports whether call calls the named method of the named type.
+func isMethod(pass *analysis.Pass, call *ast.CallExpr, pkgPath, typeName, name string) bool {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Name() != name || fn.Pkg() == nil || fn.Pkg().Path() != pkgPath {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Name() == typeName
+}
+
+// findPackage finds the package with the given path among pkg and its
+// transitive imports.
+func findPackage(pkg *types.Package, path string) *types.Package {
+	seen := make(map[*types.Package]bool)
+	var find func(p *types.Package) *types.Package
+	find = func(p *types.Package) *types.Package {
+		if p.Path() == path {
+			return p
+		}
+		if seen[p] {
+			return nil
+		}
+		seen[p] = true
+		for _, imp := range p.Imports() {
+			if found := find(imp); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return find(pkg)
+}
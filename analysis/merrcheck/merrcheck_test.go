@@ -0,0 +1,12 @@
+package merrcheck_test
+
+import (
+	"testing"
+
+	"github.com/mandacode-com/merr/analysis/merrcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), merrcheck.Analyzer, "a")
+}
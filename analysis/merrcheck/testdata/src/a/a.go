@@ -0,0 +1,80 @@
+package a // want package:`registered\(order_not_found\)`
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"b"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mandacode-com/merr"
+	merrmid "github.com/mandacode-com/merr/middleware"
+)
+
+const (
+	errOrderNotFound merr.ErrCode = "order_not_found"
+	errUnregistered  merr.ErrCode = "unregistered"
+)
+
+func init() {
+	merr.MustRegister(errOrderNotFound, merr.CodeSpec{HTTPStatus: 404})
+}
+
+func handler(c *gin.Context) {
+	c.Error(errors.New("boom"))                   // want `non-public error from errors.New reaches \(\*gin.Context\).Error`
+	c.Error(fmt.Errorf("load: %d", 1))            // want `non-public error from fmt.Errorf reaches \(\*gin.Context\).Error`
+	c.AbortWithError(500, &os.PathError{})        // want `non-public error from \*os.PathError reaches \(\*gin.Context\).AbortWithError`
+	merrmid.AbortWithError(c, errors.New("boom")) // want `non-public error from errors.New reaches merrmid.AbortWithError`
+	c.Error(merr.New(merr.ErrNotFound, "not found", nil))
+	c.Error(fmt.Errorf("load: %w", merr.New(merr.ErrNotFound, "not found", nil)))
+	c.Error(loadUser())
+}
+
+func loadUser() error { return nil }
+
+func constructors(err error) {
+	merr.New(merr.ErrNotFound, "", err)                  // want `merr.New called with an empty public message`
+	merr.WrapCode(err, merr.ErrInvalidInput, " ")        // want `merr.WrapCode called with an empty public message`
+	merr.New(errUnregistered, "oops", err)               // want `merr.New called with unregistered code "unregistered"`
+	merr.NewWithoutStack("typo_code", "oops", err)       // want `merr.NewWithoutStack called with unregistered code "typo_code"`
+	merr.Newf(merr.ErrNotFound, "", nil, err)            // want `merr.Newf called with an empty public message`
+	merr.Newf("typo_code", "order {id}", nil, err)       // want `merr.Newf called with unregistered code "typo_code"`
+	merrmid.AbortWithPublicError(nil, "other", "x", err) // want `merrmid.AbortWithPublicError called with unregistered code "other"`
+	merr.New(errOrderNotFound, "order not found", err)
+	merr.Newf(errOrderNotFound, "order {id} not found", map[string]any{"id": 1}, err)
+	merr.New(b.ErrPaymentDeclined, "payment declined", err)
+	merr.New(merr.ErrCode(codeFromConfig()), "dynamic", err)
+}
+
+func codeFromConfig() string { return "" }
+
+type UnimplementedOrdersServer struct{}
+
+type ordersServer struct {
+	UnimplementedOrdersServer
+}
+
+func (s *ordersServer) GetOrder(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", errors.New("missing id") // want `non-public error from errors.New reaches gRPC method GetOrder`
+	}
+	if id == "x" {
+		return "", merr.New(merr.ErrNotFound, "order not found", nil)
+	}
+	go func() error { return errors.New("not returned by the method") }()
+	return "", loadOrder()
+}
+
+func (s *ordersServer) Watch(id string) error {
+	return fmt.Errorf("watch %s", id) // want `non-public error from fmt.Errorf reaches gRPC method Watch`
+}
+
+func loadOrder() error { return nil }
+
+type notAService struct{}
+
+func (notAService) Get() error {
+	return errors.New("fine outside gRPC services")
+}
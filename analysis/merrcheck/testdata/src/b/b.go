@@ -0,0 +1,9 @@
+package b
+
+import "github.com/mandacode-com/merr"
+
+const ErrPaymentDeclined merr.ErrCode = "payment_declined"
+
+func init() {
+	merr.MustRegister(ErrPaymentDeclined, merr.CodeSpec{HTTPStatus: 402})
+}
@@ -0,0 +1,9 @@
+// Package gin is a stub of the real package for analyzer tests.
+package gin
+
+type Error struct{ Err error }
+
+type Context struct{}
+
+func (c *Context) Error(err error) *Error                    { return nil }
+func (c *Context) AbortWithError(code int, err error) *Error { return nil }
@@ -0,0 +1,31 @@
+// Package merr is a stub of the real package for analyzer tests.
+package merr
+
+type ErrCode string
+
+const (
+	ErrNotFound     ErrCode = "not_found"
+	ErrInvalidInput ErrCode = "invalid_input"
+)
+
+type PublicErr interface {
+	error
+	Public() string
+	Code() ErrCode
+}
+
+type Error interface {
+	PublicErr
+}
+
+type CodeSpec struct {
+	HTTPStatus int
+}
+
+func New(code ErrCode, public string, err error) Error                           { return nil }
+func Newf(code ErrCode, template string, params map[string]any, err error) Error { return nil }
+func NewWithoutStack(code ErrCode, public string, err error) Error               { return nil }
+func WrapCode(err error, code ErrCode, public string) Error                      { return nil }
+func Wrap(err error, msg string) error                                           { return nil }
+func Register(code ErrCode, spec CodeSpec) error                                 { return nil }
+func MustRegister(code ErrCode, spec CodeSpec)                                   {}
@@ -0,0 +1,10 @@
+// Package merrmid is a stub of the real package for analyzer tests.
+package merrmid
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mandacode-com/merr"
+)
+
+func AbortWithError(c *gin.Context, err error)                                             {}
+func AbortWithPublicError(c *gin.Context, code merr.ErrCode, public string, baseErr error) {}
@@ -0,0 +1,83 @@
+package merr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+)
+
+// Classifier returns the code of err, or "" if it does not recognize err.
+type Classifier func(err error) ErrCode
+
+var classifiers struct {
+	sync.RWMutex
+	list []Classifier
+}
+
+// builtinClassifications maps standard library sentinels to codes.
+// Entries are matched with errors.Is, in order.
+var builtinClassifications = []struct {
+	target error
+	code   ErrCode
+}{
+	{context.DeadlineExceeded, ErrTimeout},
+	{os.ErrDeadlineExceeded, ErrTimeout},
+	{context.Canceled, ErrCanceled},
+	{sql.ErrNoRows, ErrNotFound},
+	{fs.ErrNotExist, ErrNotFound},
+	{fs.ErrPermission, ErrPermissionDenied},
+	{fs.ErrExist, ErrConflict},
+	{sql.ErrConnDone, ErrServiceUnavailable},
+	{driver.ErrBadConn, ErrServiceUnavailable},
+}
+
+// RegisterClassifier adds a classifier consulted by Classify before the
+// built-in table. Classifiers registered later are consulted first.
+// It is safe for concurrent use.
+func RegisterClassifier(c Classifier) {
+	classifiers.Lock()
+	defer classifiers.Unlock()
+
+	classifiers.list = append(classifiers.list, c)
+}
+
+// Classify returns the code of err. The code of the first PublicErr in
+// err's chain wins; otherwise the registered classifiers and then the
+// built-in table of standard library errors are consulted, such as
+// context.DeadlineExceeded for ErrTimeout, sql.ErrNoRows and
+// fs.ErrNotExist for ErrNotFound, or net.Error timeouts for
+// ErrGatewayTimeout. Classify returns ErrUnknown if nothing matches and
+// "" if err is nil.
+func Classify(err error) ErrCode {
+	if err == nil {
+		return ""
+	}
+	if pe, ok := AsPublic(err); ok {
+		return pe.Code()
+	}
+
+	classifiers.RLock()
+	list := classifiers.list
+	classifiers.RUnlock()
+	for i := len(list) - 1; i >= 0; i-- {
+		if code := list[i](err); code != "" {
+			return code
+		}
+	}
+
+	for _, c := range builtinClassifications {
+		if errors.Is(err, c.target) {
+			return c.code
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrGatewayTimeout
+	}
+	return ErrUnknown
+}
@@ -0,0 +1,61 @@
+package merr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// timeoutError is a net.Error reporting a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrCode
+	}{
+		{nil, ""},
+		{context.DeadlineExceeded, ErrTimeout},
+		{os.ErrDeadlineExceeded, ErrTimeout},
+		{context.Canceled, ErrCanceled},
+		{fmt.Errorf("find order: %w", sql.ErrNoRows), ErrNotFound},
+		{&fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist}, ErrNotFound},
+		{fs.ErrPermission, ErrPermissionDenied},
+		{fs.ErrExist, ErrConflict},
+		{sql.ErrConnDone, ErrServiceUnavailable},
+		{&net.OpError{Op: "dial", Err: timeoutError{}}, ErrGatewayTimeout},
+		{Wrap(New(ErrConflict, "Version mismatch", sql.ErrNoRows), "save"), ErrConflict},
+		{errors.New("boom"), ErrUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Classify(tt.err), "%v", tt.err)
+	}
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	errQuota := errors.New("quota exceeded")
+	RegisterClassifier(func(err error) ErrCode {
+		if errors.Is(err, errQuota) || errors.Is(err, sql.ErrNoRows) {
+			return ErrTooManyRequests
+		}
+		return ""
+	})
+	t.Cleanup(func() { classifiers.list = nil })
+
+	assert.Equal(t, ErrTooManyRequests, Classify(fmt.Errorf("charge: %w", errQuota)))
+	assert.Equal(t, ErrTooManyRequests, Classify(sql.ErrNoRows), "registered classifiers should take precedence over built-ins")
+	assert.Equal(t, ErrNotFound, Classify(fs.ErrNotExist))
+}
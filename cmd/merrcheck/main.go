@@ -0,0 +1,14 @@
+// Command merrcheck runs the merrcheck analyzer, standalone or through go vet:
+//
+//	go install github.com/mandacode-com/merr/cmd/merrcheck@latest
+//	go vet -vettool=$(which merrcheck) ./...
+package main
+
+import (
+	"github.com/mandacode-com/merr/analysis/merrcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(merrcheck.Analyzer)
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mandacode-com/merr"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog is the error catalog read by merrgen
+type Catalog struct {
+	// Package is the name of the generated package, used when -pkg and $GOPACKAGE are unset
+	Package string  `json:"package" yaml:"package"`
+	Codes   []Entry `json:"codes" yaml:"codes"`
+}
+
+// Entry describes a single error code of the catalog
+type Entry struct {
+	// Code is the error code, e.g. "order_not_found"
+	Code string `json:"code" yaml:"code"`
+	// Name overrides the Go name derived from the code, e.g. "OrderNotFound"
+	Name string `json:"name" yaml:"name"`
+	// Parent is the code refined by this one, from the catalog or registered elsewhere
+	Parent string `json:"parent" yaml:"parent"`
+	// HTTP is the HTTP status (default: inherited from the parent)
+	HTTP int `json:"http" yaml:"http"`
+	// GRPC is the gRPC code name, e.g. "NotFound" (default: inherited from the parent)
+	GRPC string `json:"grpc" yaml:"grpc"`
+	// Title is a short summary of the code
+	Title string `json:"title" yaml:"title"`
+	// Public is the default public message of the generated constructor
+	Public string `json:"public" yaml:"public"`
+	// Retryable reports whether clients may retry (default: inherited from the parent)
+	Retryable *bool `json:"retryable" yaml:"retryable"`
+	// Doc documents when the code is used
+	Doc string `json:"doc" yaml:"doc"`
+	// Fields are the parameters of the generated constructor, attached as merr fields
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+var (
+	codePattern  = regexp.MustCompile(`^[a-z][a-z0-9_.-]*$`)
+	identPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// grpcCodes maps gRPC code names such as "NotFound" to codes
+var grpcCodes = func() map[string]codes.Code {
+	m := make(map[string]codes.Code)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[c.String()] = c
+	}
+	return m
+}()
+
+// LoadCatalog reads a YAML or JSON catalog, choosing the format by extension
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog Catalog
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &catalog)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &catalog)
+	default:
+		return nil, fmt.Errorf("%s: unsupported catalog format, want .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := catalog.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &catalog, nil
+}
+
+// Validate checks the catalog for malformed, duplicate or cyclic entries,
+// and for codes already defined by merr
+func (c *Catalog) Validate() error {
+	seen := make(map[string]bool)
+	names := make(map[string]string)
+	for _, e := range c.Codes {
+		if !codePattern.MatchString(e.Code) {
+			return fmt.Errorf("invalid code %q", e.Code)
+		}
+		if seen[e.Code] {
+			return fmt.Errorf("duplicate code %q", e.Code)
+		}
+		seen[e.Code] = true
+		if _, exists := merr.Lookup(merr.ErrCode(e.Code)); exists {
+			return fmt.Errorf("code %q is already defined by merr", e.Code)
+		}
+		if e.Name != "" && !identPattern.MatchString(e.Name) {
+			return fmt.Errorf("code %q: invalid name %q", e.Code, e.Name)
+		}
+
+		if other, exists := names[e.GoName()]; exists {
+			return fmt.Errorf("codes %q and %q both generate %s", other, e.Code, e.ConstName())
+		}
+		names[e.GoName()] = e.Code
+
+		if e.GRPC != "" {
+			if _, ok := grpcCodes[e.GRPC]; !ok {
+				return fmt.Errorf("code %q: unknown gRPC code %q", e.Code, e.GRPC)
+			}
+		}
+		if e.HTTP != 0 && (e.HTTP < 100 || e.HTTP > 599) {
+			return fmt.Errorf("code %q: invalid HTTP status %d", e.Code, e.HTTP)
+		}
+		params := make(map[string]bool)
+		for _, f := range e.Fields {
+			if !identPattern.MatchString(f) {
+				return fmt.Errorf("code %q: invalid field %q", e.Code, f)
+			}
+			if params[camelCase(f)] {
+				return fmt.Errorf("code %q: duplicate field %q", e.Code, f)
+			}
+			params[camelCase(f)] = true
+		}
+	}
+	_, err := c.Sorted()
+	return err
+}
+
+// Sorted returns the entries with parents before their children, keeping
+// the catalog order otherwise, so that registration succeeds
+func (c *Catalog) Sorted() ([]Entry, error) {
+	byCode := make(map[string]Entry, len(c.Codes))
+	for _, e := range c.Codes {
+		byCode[e.Code] = e
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	sorted := make([]Entry, 0, len(c.Codes))
+
+	var visit func(e Entry) error
+	visit = func(e Entry) error {
+		switch state[e.Code] {
+		case visiting:
+			return fmt.Errorf("code %q has a cyclic parent chain", e.Code)
+		case done:
+			return nil
+		}
+		state[e.Code] = visiting
+		if parent, ok := byCode[e.Parent]; ok {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[e.Code] = done
+		sorted = append(sorted, e)
+		return nil
+	}
+
+	for _, e := range c.Codes {
+		if err := visit(e); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// GoName returns the Go name of the entry, e.g. "OrderNotFound"
+func (e Entry) GoName() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return pascalCase(e.Code)
+}
+
+// PublicMessage returns the public message of the generated constructor,
+// defaulting to the title and then to a title derived from the code like merr does
+func (e Entry) PublicMessage() string {
+	if e.Public != "" {
+		return e.Public
+	}
+	if e.Title != "" {
+		return e.Title
+	}
+	title := strings.Join(splitWords(e.Code), " ")
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// ConstName returns the name of the generated constant
+func (e Entry) ConstName() string {
+	return "Err" + e.GoName()
+}
+
+// initialisms are kept upper case in generated names
+var initialisms = map[string]bool{
+	"API": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "UID": true, "URI": true, "URL": true, "UUID": true,
+}
+
+// pascalCase converts "order_id" to "OrderID"
+func pascalCase(s string) string {
+	words := splitWords(s)
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	return b.String()
+}
+
+// reservedParams are the identifiers used by the generated constructors,
+// which parameters must not shadow
+var reservedParams = map[string]bool{"merr": true, "codes": true, "nil": true}
+
+// camelCase converts "order_id" to "orderID"
+func camelCase(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	name := strings.ToLower(words[0]) + pascalCase(strings.Join(words[1:], "_"))
+	if token.IsKeyword(name) || reservedParams[name] {
+		name += "Value"
+	}
+	return name
+}
+
+// splitWords splits a code or field name into words
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == '.' || r == ' '
+	})
+}
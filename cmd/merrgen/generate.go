@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// GenerateGo renders the Go source of the catalog: typed constants,
+// registry registration and constructor helpers
+func GenerateGo(c *Catalog, pkg string) ([]byte, error) {
+	entries, err := c.Sorted()
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		byCode[e.Code] = e
+	}
+
+	data := struct {
+		Package  string
+		Entries  []Entry
+		UsesGRPC bool
+	}{
+		Package: pkg,
+		Entries: c.Codes,
+	}
+	for _, e := range entries {
+		if e.GRPC != "" {
+			data.UsesGRPC = true
+		}
+	}
+
+	funcs := template.FuncMap{
+		"sorted": func() []Entry { return entries },
+		"quote":  strconv.Quote,
+		"parent": func(e Entry) string {
+			if p, ok := byCode[e.Parent]; ok {
+				return p.ConstName()
+			}
+			return strconv.Quote(e.Parent)
+		},
+		"comment": comment,
+		"params": func(e Entry) string {
+			params := make([]string, len(e.Fields))
+			for i, f := range e.Fields {
+				params[i] = camelCase(f)
+			}
+			if len(params) == 0 {
+				return ""
+			}
+			return strings.Join(params, ", ") + " any"
+		},
+		"camel": camelCase,
+		"retryability": func(retryable *bool) string {
+			if *retryable {
+				return "merr.RetryAllowed"
+			}
+			return "merr.RetryDenied"
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := template.Must(template.New("go").Funcs(funcs).Parse(goTemplate)).Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// GenerateMarkdown renders the catalog as a Markdown reference table
+func GenerateMarkdown(c *Catalog) []byte {
+	var b bytes.Buffer
+	b.WriteString("<!-- Code generated by merrgen. DO NOT EDIT. -->\n\n")
+	b.WriteString("# Error codes\n\n")
+	b.WriteString("| Code | Parent | HTTP | gRPC | Retryable | Public message | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, e := range c.Codes {
+		httpStatus, grpcCode := "inherited", "inherited"
+		if e.Parent == "" {
+			httpStatus, grpcCode = "500", "Unknown"
+		}
+		if e.HTTP != 0 {
+			httpStatus = strconv.Itoa(e.HTTP)
+		}
+		if e.GRPC != "" {
+			grpcCode = e.GRPC
+		}
+		retryable := "inherited"
+		if e.Parent == "" {
+			retryable = "no"
+		}
+		if e.Retryable != nil {
+			retryable = "no"
+			if *e.Retryable {
+				retryable = "yes"
+			}
+		}
+		parent := ""
+		if e.Parent != "" {
+			parent = "`" + e.Parent + "`"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s | %s | %s |\n",
+			e.Code, parent, httpStatus, grpcCode, retryable,
+			markdownCell(e.PublicMessage()), markdownCell(e.Doc))
+	}
+	return b.Bytes()
+}
+
+// comment renders text as the body of an indented Go line comment
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	return strings.Join(lines, "\n\t// ")
+}
+
+// markdownCell escapes text for use in a table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "|", `\|`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+const goTemplate = `// Code generated by merrgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/mandacode-com/merr"
+{{- if .UsesGRPC}}
+	"google.golang.org/grpc/codes"
+{{- end}}
+)
+
+const (
+{{- range .Entries}}
+	// {{.ConstName}} is the {{quote .Code}} error code.
+{{- if .Doc}}
+	// {{comment .Doc}}
+{{- end}}
+	{{.ConstName}} merr.ErrCode = {{quote .Code}}
+{{- end}}
+)
+
+func init() {
+{{- range sorted}}
+	merr.MustRegister({{.ConstName}}, merr.CodeSpec{
+{{- if .Parent}}
+		Parent: {{parent .}},
+{{- end}}
+{{- if .HTTP}}
+		HTTPStatus: {{.HTTP}},
+{{- end}}
+{{- if .GRPC}}
+		GRPCCode: codes.{{.GRPC}},
+{{- end}}
+{{- if .Title}}
+		Title: {{quote .Title}},
+{{- end}}
+{{- with .Retryable}}
+		Retryable: {{retryability .}},
+{{- end}}
+{{- if .Doc}}
+		Description: {{quote .Doc}},
+{{- end}}
+	})
+{{- end}}
+}
+{{range .Entries}}
+// New{{.GoName}} creates an error with code {{.ConstName}}{{if .Fields}} and the given fields attached{{end}}.
+func New{{.GoName}}({{params .}}) merr.Error {
+	return merr.New({{.ConstName}}, {{quote .PublicMessage}}, nil){{range .Fields}}.
+		With({{quote .}}, {{camel .}}){{end}}
+}
+{{end}}`
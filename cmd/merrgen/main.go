@@ -0,0 +1,79 @@
+// Command merrgen generates merr error codes from a YAML or JSON catalog.
+//
+// It emits typed constants, their registration in the merr registry,
+// constructor helpers and, optionally, a Markdown reference table:
+//
+//	//go:generate go run github.com/mandacode-com/merr/cmd/merrgen -catalog errors.yaml -out errors_gen.go -doc ERRORS.md
+//
+// A catalog lists codes with their parent, mappings and default public message:
+//
+//	package: orders
+//	codes:
+//	  - code: order_not_found
+//	    parent: not_found
+//	    public: Order not found
+//	    doc: Returned when no order matches the requested ID.
+//	    fields: [order_id]
+//	  - code: payment_declined
+//	    http: 402
+//	    grpc: FailedPrecondition
+//	    public: Payment declined
+//
+// The package name defaults to $GOPACKAGE, as set by go generate.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	catalogPath := flag.String("catalog", "", "path of the YAML or JSON error catalog (required)")
+	out := flag.String("out", "errors_gen.go", "path of the generated Go file")
+	doc := flag.String("doc", "", "path of the generated Markdown reference (optional)")
+	pkg := flag.String("pkg", "", "package name of the generated file (default: $GOPACKAGE or the catalog package)")
+	flag.Parse()
+
+	if err := run(*catalogPath, *out, *doc, *pkg); err != nil {
+		fmt.Fprintf(os.Stderr, "merrgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(catalogPath, out, doc, pkg string) error {
+	if catalogPath == "" {
+		flag.Usage()
+		return fmt.Errorf("-catalog is required")
+	}
+
+	catalog, err := LoadCatalog(catalogPath)
+	if err != nil {
+		return err
+	}
+
+	if pkg == "" {
+		pkg = os.Getenv("GOPACKAGE")
+	}
+	if pkg == "" {
+		pkg = catalog.Package
+	}
+	if pkg == "" {
+		return fmt.Errorf("no package name: set -pkg, $GOPACKAGE or the catalog package")
+	}
+
+	src, err := GenerateGo(catalog, pkg)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		return err
+	}
+
+	if doc != "" {
+		if err := os.WriteFile(doc, GenerateMarkdown(catalog), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
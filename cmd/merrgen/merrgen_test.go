@@ -0,0 +1,138 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceImporter is shared by the tests, as importing gRPC from source is slow
+var sourceImporter = importer.ForCompiler(token.NewFileSet(), "source", nil)
+
+// typeCheck parses and type-checks generated source against the real merr and gRPC packages
+func typeCheck(t *testing.T, src []byte) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "gen.go", src, 0)
+	require.NoError(t, err, "generated code should parse")
+
+	conf := types.Config{Importer: sourceImporter}
+	_, err = conf.Check(file.Name.Name, fset, []*ast.File{file}, nil)
+	require.NoError(t, err, "generated code should type-check")
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	catalog, err := LoadCatalog("testdata/catalog.yaml")
+	require.NoError(t, err)
+
+	src, err := GenerateGo(catalog, "orders")
+	require.NoError(t, err)
+	want, err := os.ReadFile("testdata/catalog_gen.go.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(src))
+	typeCheck(t, want)
+
+	want, err = os.ReadFile("testdata/catalog.md.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(GenerateMarkdown(catalog)))
+}
+
+func TestGenerate_JSON(t *testing.T) {
+	catalog, err := LoadCatalog("testdata/catalog.json")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", catalog.Package)
+
+	src, err := GenerateGo(catalog, catalog.Package)
+	require.NoError(t, err)
+
+	typeCheck(t, src)
+	assert.Contains(t, string(src), `ErrInvoiceOverdue merr.ErrCode = "invoice_overdue"`)
+	assert.Contains(t, string(src), "func NewInvoiceOverdue(invoiceID any) merr.Error {")
+}
+
+func TestGenerate_ReservedFields(t *testing.T) {
+	catalog := &Catalog{Codes: []Entry{
+		{Code: "shadowed", GRPC: "NotFound", Fields: []string{"merr", "codes", "nil", "type"}},
+	}}
+	require.NoError(t, catalog.Validate())
+
+	src, err := GenerateGo(catalog, "shadow")
+	require.NoError(t, err)
+	typeCheck(t, src)
+	assert.Contains(t, string(src), "func NewShadowed(merrValue, codesValue, nilValue, typeValue any) merr.Error {")
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "errors_gen.go")
+	doc := filepath.Join(dir, "ERRORS.md")
+
+	t.Setenv("GOPACKAGE", "generated")
+	require.NoError(t, run("testdata/catalog.json", out, doc, ""))
+
+	src, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "package generated", "$GOPACKAGE should win over the catalog package")
+	assert.FileExists(t, doc)
+
+	assert.Error(t, run("", out, doc, ""), "the catalog is required")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog Catalog
+	}{
+		{"invalid code", Catalog{Codes: []Entry{{Code: "Not Valid"}}}},
+		{"built-in code", Catalog{Codes: []Entry{{Code: "not_found"}}}},
+		{"invalid name", Catalog{Codes: []Entry{{Code: "a", Name: "Not-Valid"}}}},
+		{"duplicate code", Catalog{Codes: []Entry{{Code: "a"}, {Code: "a"}}}},
+		{"duplicate name", Catalog{Codes: []Entry{{Code: "a_b"}, {Code: "a-b"}}}},
+		{"unknown gRPC code", Catalog{Codes: []Entry{{Code: "a", GRPC: "Missing"}}}},
+		{"invalid HTTP status", Catalog{Codes: []Entry{{Code: "a", HTTP: 42}}}},
+		{"invalid field", Catalog{Codes: []Entry{{Code: "a", Fields: []string{"1st"}}}}},
+		{"duplicate field", Catalog{Codes: []Entry{{Code: "a", Fields: []string{"id", "ID"}}}}},
+		{"cycle", Catalog{Codes: []Entry{{Code: "a", Parent: "b"}, {Code: "b", Parent: "a"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.catalog.Validate())
+		})
+	}
+}
+
+func TestSorted_ParentsFirst(t *testing.T) {
+	catalog := Catalog{Codes: []Entry{
+		{Code: "child", Parent: "parent"},
+		{Code: "parent", Parent: "not_found"},
+		{Code: "other"},
+	}}
+
+	sorted, err := catalog.Sorted()
+	require.NoError(t, err)
+
+	var order []string
+	for _, e := range sorted {
+		order = append(order, e.Code)
+	}
+	assert.Equal(t, []string{"parent", "child", "other"}, order)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "OrderID", pascalCase("order_id"))
+	assert.Equal(t, "orderID", camelCase("order_id"))
+	assert.Equal(t, "idempotencyKey", camelCase("idempotency-key"))
+	assert.Equal(t, "urlPath", camelCase("url.path"))
+	assert.Equal(t, "funcValue", camelCase("func"))
+	assert.Equal(t, "merrValue", camelCase("merr"))
+	assert.Equal(t, "PaymentDeclined", Entry{Code: "payment_declined"}.GoName())
+	assert.Equal(t, "Declined", Entry{Code: "payment_declined", Name: "Declined"}.GoName())
+}
@@ -0,0 +1,95 @@
+package merr
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+// Every code in codes.go with its forward mappings and the code the
+// reverse mappings give back.
+var codeMappings = []struct {
+	code     ErrCode
+	status   int
+	fromHTTP ErrCode
+	grpc     codes.Code
+	fromGRPC ErrCode
+}{
+	{ErrUnknown, http.StatusInternalServerError, ErrInternalServerError, codes.Unknown, ErrUnknown},
+	{ErrNotFound, http.StatusNotFound, ErrNotFound, codes.NotFound, ErrNotFound},
+	{ErrInvalidInput, http.StatusBadRequest, ErrBadRequest, codes.InvalidArgument, ErrInvalidInput},
+	{ErrPermissionDenied, http.StatusForbidden, ErrForbidden, codes.PermissionDenied, ErrPermissionDenied},
+	{ErrInternalServerError, http.StatusInternalServerError, ErrInternalServerError, codes.Internal, ErrInternalServerError},
+	{ErrTimeout, http.StatusGatewayTimeout, ErrGatewayTimeout, codes.DeadlineExceeded, ErrTimeout},
+	{ErrConflict, http.StatusConflict, ErrConflict, codes.Aborted, ErrConflict},
+	{ErrUnauthorized, http.StatusUnauthorized, ErrUnauthorized, codes.Unauthenticated, ErrUnauthorized},
+	{ErrBadRequest, http.StatusBadRequest, ErrBadRequest, codes.InvalidArgument, ErrInvalidInput},
+	{ErrServiceUnavailable, http.StatusServiceUnavailable, ErrServiceUnavailable, codes.Unavailable, ErrServiceUnavailable},
+	{ErrTooManyRequests, http.StatusTooManyRequests, ErrTooManyRequests, codes.ResourceExhausted, ErrTooManyRequests},
+	{ErrGatewayTimeout, http.StatusGatewayTimeout, ErrGatewayTimeout, codes.DeadlineExceeded, ErrTimeout},
+	{ErrUnprocessableEntity, http.StatusUnprocessableEntity, ErrUnprocessableEntity, codes.FailedPrecondition, ErrPreconditionFailed},
+	{ErrNotImplemented, http.StatusNotImplemented, ErrNotImplemented, codes.Unimplemented, ErrNotImplemented},
+	{ErrMethodNotAllowed, http.StatusMethodNotAllowed, ErrMethodNotAllowed, codes.Unimplemented, ErrNotImplemented},
+	{ErrForbidden, http.StatusForbidden, ErrForbidden, codes.PermissionDenied, ErrPermissionDenied},
+	{ErrPreconditionFailed, http.StatusPreconditionFailed, ErrPreconditionFailed, codes.FailedPrecondition, ErrPreconditionFailed},
+	{ErrExpectationFailed, http.StatusExpectationFailed, ErrExpectationFailed, codes.FailedPrecondition, ErrPreconditionFailed},
+	{ErrBadGateway, http.StatusBadGateway, ErrBadGateway, codes.Unavailable, ErrServiceUnavailable},
+	{ErrLengthRequired, http.StatusLengthRequired, ErrLengthRequired, codes.InvalidArgument, ErrInvalidInput},
+	{ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType, codes.InvalidArgument, ErrInvalidInput},
+	{ErrRangeNotSatisfiable, http.StatusRequestedRangeNotSatisfiable, ErrRangeNotSatisfiable, codes.OutOfRange, ErrRangeNotSatisfiable},
+	{ErrInsufficientStorage, http.StatusInsufficientStorage, ErrInsufficientStorage, codes.ResourceExhausted, ErrTooManyRequests},
+	{ErrLoopDetected, http.StatusLoopDetected, ErrLoopDetected, codes.Aborted, ErrConflict},
+	{ErrNotAcceptable, http.StatusNotAcceptable, ErrNotAcceptable, codes.InvalidArgument, ErrInvalidInput},
+	{ErrTooEarly, http.StatusTooEarly, ErrTooEarly, codes.FailedPrecondition, ErrPreconditionFailed},
+	{ErrRequestHeaderFieldsTooLarge, http.StatusRequestHeaderFieldsTooLarge, ErrRequestHeaderFieldsTooLarge, codes.ResourceExhausted, ErrTooManyRequests},
+	{ErrCanceled, StatusClientClosedRequest, ErrCanceled, codes.Canceled, ErrCanceled},
+}
+
+func TestCodeMappings_RoundTrip(t *testing.T) {
+	for _, m := range codeMappings {
+		t.Run(string(m.code), func(t *testing.T) {
+			assert.Equal(t, m.status, m.code.ToHTTPStatus())
+			assert.Equal(t, m.fromHTTP, FromHTTPStatus(m.status))
+			assert.Equal(t, m.status, FromHTTPStatus(m.status).ToHTTPStatus(), "HTTP round trip should preserve the status")
+
+			assert.Equal(t, m.grpc, m.code.ToGRPCCode())
+			assert.Equal(t, m.fromGRPC, FromGRPCCode(m.grpc))
+			assert.Equal(t, m.grpc, FromGRPCCode(m.grpc).ToGRPCCode(), "gRPC round trip should preserve the code")
+		})
+	}
+}
+
+func TestCodeMappings_CoverEveryCode(t *testing.T) {
+	covered := make(map[ErrCode]bool)
+	for _, m := range codeMappings {
+		covered[m.code] = true
+	}
+	for code := range httpErrorMap {
+		assert.True(t, covered[code], "%s is missing from the mapping table", code)
+	}
+	for code := range grpcErrorMap {
+		assert.True(t, covered[code], "%s is missing from the mapping table", code)
+	}
+}
+
+func TestFromHTTPStatus_Unlisted(t *testing.T) {
+	assert.Equal(t, ErrBadRequest, FromHTTPStatus(http.StatusRequestTimeout), "408 must not map to a 5xx code")
+	assert.Equal(t, ErrNotFound, FromHTTPStatus(http.StatusGone))
+	assert.Equal(t, ErrBadRequest, FromHTTPStatus(http.StatusTeapot))
+	assert.Equal(t, ErrInternalServerError, FromHTTPStatus(http.StatusHTTPVersionNotSupported))
+	assert.Equal(t, ErrUnknown, FromHTTPStatus(http.StatusOK))
+}
+
+func TestFromHTTPStatus_SameClass(t *testing.T) {
+	for status := range httpStatusMap {
+		assert.Equal(t, status/100, FromHTTPStatus(status).ToHTTPStatus()/100, "status %d", status)
+	}
+}
+
+func TestFromGRPCCode_Unlisted(t *testing.T) {
+	assert.Equal(t, ErrConflict, FromGRPCCode(codes.AlreadyExists))
+	assert.Equal(t, ErrInternalServerError, FromGRPCCode(codes.DataLoss))
+	assert.Equal(t, ErrUnknown, FromGRPCCode(codes.OK))
+}
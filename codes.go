@@ -30,14 +30,23 @@ const (
 	ErrNotAcceptable               ErrCode = "not_acceptable"
 	ErrTooEarly                    ErrCode = "too_early"
 	ErrRequestHeaderFieldsTooLarge ErrCode = "request_header_fields_too_large"
+	ErrCanceled                    ErrCode = "canceled"
 )
 
+// Error implements the error interface so that codes can be used as
+// sentinels with errors.Is.
+func (e ErrCode) Error() string {
+	return string(e)
+}
+
+// CheckCode reports whether the first PublicErr in err's chain has the given
+// code or one of its descendants.
 func CheckCode(err error, code ErrCode) bool {
 	if err == nil {
 		return false
 	}
-	if serr, ok := err.(PublicErr); ok {
-		return serr.Code() == code
+	if serr, ok := AsPublic(err); ok {
+		return serr.Code().IsA(code)
 	}
 	return false
 }
@@ -0,0 +1,80 @@
+package merr
+
+import "slices"
+
+// Field is a key/value pair attached to an error for logging.
+// Fields are never included in the public message.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// fielder is implemented by errors that carry their own fields.
+type fielder interface {
+	ownFields() []Field
+}
+
+// WithField attaches a key/value field to err.
+// If err is a merr error the result keeps its code and public message,
+// otherwise err is wrapped without becoming a PublicErr.
+// WithField returns nil if err is nil.
+func WithField(e error, key string, value any) error {
+	if e == nil {
+		return nil
+	}
+	switch e := e.(type) {
+	case *err:
+		return e.With(key, value)
+	case *wrapErr:
+		c := *e
+		c.fields = appendField(e.fields, key, value)
+		return &c
+	}
+	return &wrapErr{cause: e, fields: []Field{{Key: key, Value: value}}}
+}
+
+// Fields returns the fields attached across err's chain, innermost first.
+// When the same key appears more than once, the most recently attached
+// value wins.
+func Fields(err error) []Field {
+	var fields []Field
+	seen := make(map[string]bool)
+	walk(err, func(e error) {
+		f, ok := e.(fielder)
+		if !ok {
+			return
+		}
+		own := f.ownFields()
+		for i := len(own) - 1; i >= 0; i-- {
+			if !seen[own[i].Key] {
+				seen[own[i].Key] = true
+				fields = append(fields, own[i])
+			}
+		}
+	})
+	slices.Reverse(fields)
+	return fields
+}
+
+// appendField returns a new slice with the field appended, leaving fields untouched.
+func appendField(fields []Field, key string, value any) []Field {
+	out := make([]Field, 0, len(fields)+1)
+	out = append(out, fields...)
+	return append(out, Field{Key: key, Value: value})
+}
+
+// walk calls fn for err and every error in its chain, depth first.
+func walk(err error, fn func(error)) {
+	if err == nil {
+		return
+	}
+	fn(err)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			walk(e, fn)
+		}
+	}
+}
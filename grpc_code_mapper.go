@@ -2,7 +2,7 @@ package merr
 
 import "google.golang.org/grpc/codes"
 
-// error map
+// error map of the built-in codes, loaded into the registry
 var grpcErrorMap = map[ErrCode]codes.Code{
 	ErrUnknown:                     codes.Unknown,
 	ErrNotFound:                    codes.NotFound,
@@ -31,11 +31,56 @@ var grpcErrorMap = map[ErrCode]codes.Code{
 	ErrNotAcceptable:               codes.InvalidArgument,
 	ErrTooEarly:                    codes.FailedPrecondition,
 	ErrRequestHeaderFieldsTooLarge: codes.ResourceExhausted,
+	ErrCanceled:                    codes.Canceled,
 }
 
+// ToGRPCCode returns the gRPC code registered for the code.
+// Codes without their own gRPC code inherit the code of their parent.
 func (e ErrCode) ToGRPCCode() codes.Code {
-	if code, exists := grpcErrorMap[e]; exists {
-		return code
+	return resolveGRPCCode(e, nil)
+}
+
+// resolveGRPCCode walks the code's ancestry, preferring overrides to the
+// registry at each level.
+func resolveGRPCCode(code ErrCode, overrides map[ErrCode]codes.Code) codes.Code {
+	for c := code; c != ""; c = c.Parent() {
+		if grpcCode, exists := overrides[c]; exists {
+			return grpcCode
+		}
+		if spec, exists := Lookup(c); exists && spec.GRPCCode != codes.OK {
+			return spec.GRPCCode
+		}
 	}
 	return codes.Unknown // Default to Unknown if the error code is not mapped
 }
+
+// reverse error map, used where the forward map is many-to-one
+var grpcCodeMap = map[codes.Code]ErrCode{
+	codes.Canceled:           ErrCanceled,
+	codes.Unknown:            ErrUnknown,
+	codes.InvalidArgument:    ErrInvalidInput, // also ErrBadRequest, ErrLengthRequired, ErrUnsupportedMediaType, ErrNotAcceptable
+	codes.DeadlineExceeded:   ErrTimeout,      // also ErrGatewayTimeout
+	codes.NotFound:           ErrNotFound,
+	codes.AlreadyExists:      ErrConflict,
+	codes.PermissionDenied:   ErrPermissionDenied,   // also ErrForbidden
+	codes.ResourceExhausted:  ErrTooManyRequests,    // also ErrInsufficientStorage, ErrRequestHeaderFieldsTooLarge
+	codes.FailedPrecondition: ErrPreconditionFailed, // also ErrUnprocessableEntity, ErrExpectationFailed, ErrTooEarly
+	codes.Aborted:            ErrConflict,           // also ErrLoopDetected
+	codes.OutOfRange:         ErrRangeNotSatisfiable,
+	codes.Unimplemented:      ErrNotImplemented, // also ErrMethodNotAllowed
+	codes.Internal:           ErrInternalServerError,
+	codes.Unavailable:        ErrServiceUnavailable, // also ErrBadGateway
+	codes.DataLoss:           ErrInternalServerError,
+	codes.Unauthenticated:    ErrUnauthorized,
+}
+
+// FromGRPCCode returns the error code for a gRPC code.
+// Where several codes map to the same gRPC code, the most generic one wins
+// (e.g. InvalidArgument gives ErrInvalidInput rather than ErrBadRequest).
+// Codes without a counterpart, including OK, give ErrUnknown.
+func FromGRPCCode(code codes.Code) ErrCode {
+	if errCode, exists := grpcCodeMap[code]; exists {
+		return errCode
+	}
+	return ErrUnknown
+}
@@ -2,7 +2,11 @@ package merr
 
 import "net/http"
 
-// error map
+// StatusClientClosedRequest is the non-standard status of requests canceled
+// by the client, as used by nginx.
+const StatusClientClosedRequest = 499
+
+// error map of the built-in codes, loaded into the registry
 var httpErrorMap = map[ErrCode]int{
 	ErrUnknown:                     http.StatusInternalServerError,
 	ErrNotFound:                    http.StatusNotFound,
@@ -31,11 +35,75 @@ var httpErrorMap = map[ErrCode]int{
 	ErrNotAcceptable:               http.StatusNotAcceptable,
 	ErrTooEarly:                    http.StatusTooEarly,
 	ErrRequestHeaderFieldsTooLarge: http.StatusRequestHeaderFieldsTooLarge,
+	ErrCanceled:                    StatusClientClosedRequest,
 }
 
+// ToHTTPStatus returns the HTTP status registered for the code.
+// Codes without their own status inherit the status of their parent.
 func (e ErrCode) ToHTTPStatus() int {
-	if status, exists := httpErrorMap[e]; exists {
-		return status
+	return resolveHTTPStatus(e, nil)
+}
+
+// resolveHTTPStatus walks the code's ancestry, preferring overrides to the
+// registry at each level.
+func resolveHTTPStatus(code ErrCode, overrides map[ErrCode]int) int {
+	for c := code; c != ""; c = c.Parent() {
+		if status, exists := overrides[c]; exists {
+			return status
+		}
+		if spec, exists := Lookup(c); exists && spec.HTTPStatus != 0 {
+			return spec.HTTPStatus
+		}
 	}
 	return http.StatusInternalServerError // Default to Internal Server Error if the error code is not mapped
 }
+
+// reverse error map, used where the forward map is many-to-one or to
+// recognize statuses that no code maps to. Every entry maps back to a
+// status of the same class, so 408 is left to the ErrBadRequest fallback
+// rather than given ErrTimeout, which maps to 504.
+var httpStatusMap = map[int]ErrCode{
+	http.StatusBadRequest:                   ErrBadRequest, // also ErrInvalidInput
+	http.StatusUnauthorized:                 ErrUnauthorized,
+	http.StatusForbidden:                    ErrForbidden, // also ErrPermissionDenied
+	http.StatusNotFound:                     ErrNotFound,
+	http.StatusMethodNotAllowed:             ErrMethodNotAllowed,
+	http.StatusNotAcceptable:                ErrNotAcceptable,
+	http.StatusConflict:                     ErrConflict,
+	http.StatusGone:                         ErrNotFound,
+	http.StatusLengthRequired:               ErrLengthRequired,
+	http.StatusPreconditionFailed:           ErrPreconditionFailed,
+	http.StatusUnsupportedMediaType:         ErrUnsupportedMediaType,
+	http.StatusRequestedRangeNotSatisfiable: ErrRangeNotSatisfiable,
+	http.StatusExpectationFailed:            ErrExpectationFailed,
+	http.StatusUnprocessableEntity:          ErrUnprocessableEntity,
+	http.StatusTooEarly:                     ErrTooEarly,
+	http.StatusTooManyRequests:              ErrTooManyRequests,
+	http.StatusRequestHeaderFieldsTooLarge:  ErrRequestHeaderFieldsTooLarge,
+	StatusClientClosedRequest:               ErrCanceled,
+	http.StatusInternalServerError:          ErrInternalServerError, // also ErrUnknown
+	http.StatusNotImplemented:               ErrNotImplemented,
+	http.StatusBadGateway:                   ErrBadGateway,
+	http.StatusServiceUnavailable:           ErrServiceUnavailable,
+	http.StatusGatewayTimeout:               ErrGatewayTimeout, // also ErrTimeout
+	http.StatusInsufficientStorage:          ErrInsufficientStorage,
+	http.StatusLoopDetected:                 ErrLoopDetected,
+}
+
+// FromHTTPStatus returns the error code for an HTTP status.
+// Where several codes map to the same status, the one named after the
+// status wins (e.g. 400 gives ErrBadRequest rather than ErrInvalidInput).
+// Unlisted 4xx statuses give ErrBadRequest, unlisted 5xx statuses give
+// ErrInternalServerError and anything else gives ErrUnknown.
+func FromHTTPStatus(status int) ErrCode {
+	if code, exists := httpStatusMap[status]; exists {
+		return code
+	}
+	switch {
+	case status >= 400 && status < 500:
+		return ErrBadRequest
+	case status >= 500 && status < 600:
+		return ErrInternalServerError
+	}
+	return ErrUnknown
+}
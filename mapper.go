@@ -0,0 +1,94 @@
+package merr
+
+import "google.golang.org/grpc/codes"
+
+// Mapper maps error codes to HTTP statuses and gRPC codes using its own
+// tables, falling back to the registry for codes it does not override.
+// It lets APIs served from one binary disagree on mappings.
+// A Mapper is immutable; the With methods return a modified copy.
+// A nil *Mapper uses the registry.
+type Mapper struct {
+	http map[ErrCode]int
+	grpc map[ErrCode]codes.Code
+}
+
+// NewMapper creates a Mapper that behaves like the registry.
+func NewMapper() *Mapper {
+	return &Mapper{}
+}
+
+// WithHTTPStatus returns a copy of the mapper mapping code to status.
+func (m *Mapper) WithHTTPStatus(code ErrCode, status int) *Mapper {
+	c := m.clone()
+	c.http[code] = status
+	return c
+}
+
+// WithGRPCCode returns a copy of the mapper mapping code to grpcCode.
+func (m *Mapper) WithGRPCCode(code ErrCode, grpcCode codes.Code) *Mapper {
+	c := m.clone()
+	c.grpc[code] = grpcCode
+	return c
+}
+
+// ToHTTPStatus returns the HTTP status of the code.
+// Overrides of a parent code apply to its descendants.
+func (m *Mapper) ToHTTPStatus(code ErrCode) int {
+	if m == nil {
+		return code.ToHTTPStatus()
+	}
+	return resolveHTTPStatus(code, m.http)
+}
+
+// ToGRPCCode returns the gRPC code of the code.
+// Overrides of a parent code apply to its descendants.
+func (m *Mapper) ToGRPCCode(code ErrCode) codes.Code {
+	if m == nil {
+		return code.ToGRPCCode()
+	}
+	return resolveGRPCCode(code, m.grpc)
+}
+
+// MostSevereCode returns a CodePolicy like MostSevereCode that ranks codes
+// by the mapper's HTTP statuses.
+func (m *Mapper) MostSevereCode() CodePolicy {
+	return func(errs []PublicErr) ErrCode {
+		best := errs[0].Code()
+		for _, e := range errs[1:] {
+			if m.ToHTTPStatus(e.Code())/100 > m.ToHTTPStatus(best)/100 {
+				best = e.Code()
+			}
+		}
+		return best
+	}
+}
+
+// HighestStatusCode returns a CodePolicy like HighestStatusCode that ranks
+// codes by the mapper's HTTP statuses.
+func (m *Mapper) HighestStatusCode() CodePolicy {
+	return func(errs []PublicErr) ErrCode {
+		best := errs[0].Code()
+		for _, e := range errs[1:] {
+			if m.ToHTTPStatus(e.Code()) > m.ToHTTPStatus(best) {
+				best = e.Code()
+			}
+		}
+		return best
+	}
+}
+
+func (m *Mapper) clone() *Mapper {
+	c := &Mapper{
+		http: make(map[ErrCode]int),
+		grpc: make(map[ErrCode]codes.Code),
+	}
+	if m != nil {
+		for k, v := range m.http {
+			c.http[k] = v
+		}
+		for k, v := range m.grpc {
+			c.grpc[k] = v
+		}
+	}
+	return c
+}
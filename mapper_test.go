@@ -0,0 +1,49 @@
+package merr
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+)
+
+func TestMapper_Overrides(t *testing.T) {
+	base := NewMapper()
+	gateway := base.WithHTTPStatus(ErrConflict, http.StatusPreconditionFailed)
+	grpcAPI := base.WithGRPCCode(ErrUnprocessableEntity, codes.InvalidArgument)
+
+	assert.Equal(t, http.StatusPreconditionFailed, gateway.ToHTTPStatus(ErrConflict))
+	assert.Equal(t, http.StatusNotFound, gateway.ToHTTPStatus(ErrNotFound), "other codes should use the registry")
+	assert.Equal(t, codes.Aborted, gateway.ToGRPCCode(ErrConflict), "gRPC mapping should be unaffected")
+
+	assert.Equal(t, codes.InvalidArgument, grpcAPI.ToGRPCCode(ErrUnprocessableEntity))
+	assert.Equal(t, http.StatusConflict, grpcAPI.ToHTTPStatus(ErrConflict), "mappers should not share overrides")
+
+	assert.Equal(t, http.StatusConflict, base.ToHTTPStatus(ErrConflict), "With methods should not modify the receiver")
+	assert.Equal(t, http.StatusConflict, ErrConflict.ToHTTPStatus(), "the registry should be unaffected")
+}
+
+func TestMapper_Nil(t *testing.T) {
+	var m *Mapper
+
+	assert.Equal(t, http.StatusNotFound, m.ToHTTPStatus(ErrNotFound))
+	assert.Equal(t, codes.NotFound, m.ToGRPCCode(ErrNotFound))
+	assert.Equal(t, http.StatusTeapot, m.WithHTTPStatus(ErrNotFound, http.StatusTeapot).ToHTTPStatus(ErrNotFound))
+}
+
+func TestMapper_CodePolicies(t *testing.T) {
+	m := NewMapper().WithHTTPStatus(ErrConflict, http.StatusGatewayTimeout)
+	errs := []PublicErr{
+		New(ErrServiceUnavailable, "a", nil),
+		New(ErrConflict, "b", nil),
+	}
+
+	assert.Equal(t, ErrConflict, m.HighestStatusCode()(errs), "statuses should come from the mapper")
+	assert.Equal(t, ErrServiceUnavailable, HighestStatusCode(errs))
+
+	errs = []PublicErr{New(ErrNotFound, "a", nil), New(ErrConflict, "b", nil)}
+	assert.Equal(t, ErrConflict, m.MostSevereCode()(errs), "statuses should come from the mapper")
+	assert.Equal(t, ErrNotFound, MostSevereCode(errs))
+	assert.Equal(t, ErrNotFound, (*Mapper)(nil).MostSevereCode()(errs))
+}
@@ -2,6 +2,13 @@
 // This package provides a custom error type for handling errors in a structured way.
 package merr
 
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
 // PublicErr is an interface for errors that can be publicly displayed.
 type PublicErr interface {
 	error
@@ -9,14 +16,70 @@ type PublicErr interface {
 	Code() ErrCode
 }
 
+// Error is a PublicErr created by this package.
+// It supports attaching additional context for logging and clients.
+type Error interface {
+	PublicErr
+	StackTracer
+	// With returns a copy of the error with the key/value field attached.
+	With(key string, value any) Error
+	// Fields returns the fields attached across the error chain.
+	Fields() []Field
+	// WithMeta returns a copy of the error with public metadata attached.
+	WithMeta(key, value string) Error
+	// WithMessageKey returns a copy of the error whose public message can be
+	// translated using the key and parameters.
+	WithMessageKey(key string, params map[string]any) Error
+	// RetryAfter returns a copy of the error hinting that the request may be
+	// retried after d.
+	RetryAfter(d time.Duration) Error
+}
+
 type err struct {
 	error
+	msg    string
 	public string
 	code   ErrCode
+	stack  stack
+	fields []Field
+	meta   map[string]string
+	key    string
+	params map[string]any
+	tmpl   string
+	retry  time.Duration
 }
 
 // New creates a new error with a public message.
-func New(code ErrCode, public string, error error) error {
+// The caller's stack is captured unless disabled with SetStackCapture.
+func New(code ErrCode, public string, error error) Error {
+	return &err{
+		error:  error,
+		public: public,
+		code:   code,
+		stack:  callers(1),
+	}
+}
+
+// Newf creates a new error whose public message is rendered from a template
+// with {name} placeholders, such as "Order {id} was not found".
+// Parameter values are sanitized before rendering, so user input cannot
+// inject placeholders or control characters into the message. The template
+// and parameters are kept on the error for grouping and translation.
+func Newf(code ErrCode, template string, params map[string]any, error error) Error {
+	params = sanitizeParams(params)
+	return &err{
+		error:  error,
+		public: interpolate(template, params),
+		code:   code,
+		stack:  callers(1),
+		params: params,
+		tmpl:   template,
+	}
+}
+
+// NewWithoutStack is like New but never captures a stack trace.
+// Use it on hot paths where the cost of capture matters.
+func NewWithoutStack(code ErrCode, public string, error error) Error {
 	return &err{
 		error:  error,
 		public: public,
@@ -36,8 +99,147 @@ func (e *err) Code() ErrCode {
 
 // Error returns the error message.
 func (e *err) Error() string {
-	if e.error != nil {
+	switch {
+	case e.msg != "" && e.error != nil:
+		return e.msg + ": " + e.error.Error()
+	case e.msg != "":
+		return e.msg
+	case e.error != nil:
 		return e.error.Error()
 	}
 	return e.public
 }
+
+// Unwrap returns the underlying error, if any.
+func (e *err) Unwrap() error {
+	return e.error
+}
+
+// With returns a copy of the error with the key/value field attached.
+// Fields are intended for logging and never appear in Public().
+func (e *err) With(key string, value any) Error {
+	c := *e
+	c.fields = appendField(e.fields, key, value)
+	return &c
+}
+
+// Fields returns the fields attached across the error chain.
+func (e *err) Fields() []Field {
+	return Fields(e)
+}
+
+func (e *err) ownFields() []Field {
+	return e.fields
+}
+
+// WithMeta returns a copy of the error with public metadata attached.
+// Unlike fields, metadata is meant to be sent to clients, for example
+// in the metadata of a gRPC ErrorInfo.
+func (e *err) WithMeta(key, value string) Error {
+	c := *e
+	c.meta = make(map[string]string, len(e.meta)+1)
+	for k, v := range e.meta {
+		c.meta[k] = v
+	}
+	c.meta[key] = value
+	return &c
+}
+
+// WithMessageKey returns a copy of the error whose public message can be
+// translated by a Translator using the key and parameters. Public() keeps
+// returning the untranslated message. Nil params keep the parameters
+// given to Newf.
+func (e *err) WithMessageKey(key string, params map[string]any) Error {
+	c := *e
+	c.key = key
+	if params != nil {
+		c.params = sanitizeParams(params)
+	}
+	return &c
+}
+
+// MessageKey returns the message key of the public message.
+func (e *err) MessageKey() string {
+	return e.key
+}
+
+// MessageParams returns the parameters of the public message.
+func (e *err) MessageParams() map[string]any {
+	return e.params
+}
+
+// MessageTemplate returns the template of the public message, or ""
+// if the error was not created by Newf.
+func (e *err) MessageTemplate() string {
+	return e.tmpl
+}
+
+// RetryAfter returns a copy of the error hinting that the request may be
+// retried after d. A positive hint makes the error retryable regardless
+// of its code.
+func (e *err) RetryAfter(d time.Duration) Error {
+	c := *e
+	c.retry = d
+	return &c
+}
+
+// RetryDelay returns the retry hint of the error, or 0 if it has none.
+func (e *err) RetryDelay() time.Duration {
+	return e.retry
+}
+
+// StackTrace returns the stack captured when the error was created,
+// or nil if capture was disabled.
+func (e *err) StackTrace() []Frame {
+	return e.stack.frames()
+}
+
+// Format implements fmt.Formatter.
+// The %+v verb prints the code and public message (or the Wrap message),
+// the stack trace and the formatted chain of causes. %v and %s print
+// Error() and %q prints it quoted.
+func (e *err) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if e.msg != "" {
+				io.WriteString(s, e.msg)
+			} else {
+				fmt.Fprintf(s, "%s: %s", e.code, e.public)
+			}
+			writeFrames(s, e.StackTrace())
+			if e.error != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", e.error)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, e.Error())
+	}
+}
+
+// Is reports whether the error matches target by code, including ancestors
+// of the error's code. Target may be an ErrCode or another PublicErr.
+func (e *err) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrCode:
+		return e.code.IsA(t)
+	case PublicErr:
+		return e.code.IsA(t.Code())
+	}
+	return false
+}
+
+// AsPublic finds the first PublicErr in err's chain.
+func AsPublic(err error) (PublicErr, bool) {
+	var pe PublicErr
+	if errors.As(err, &pe) {
+		return pe, true
+	}
+	return nil, false
+}
@@ -3,6 +3,8 @@ package merr
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +61,157 @@ func TestGRPCCodeMapping(t *testing.T) {
 	assert.Equal(t, 5, int(ErrNotFound.ToGRPCCode()), "ErrNotFound should map to NotFound gRPC code")
 	assert.Equal(t, 3, int(ErrInvalidInput.ToGRPCCode()), "ErrInvalidInput should map to InvalidArgument gRPC code")
 }
+
+func TestUnwrap_ChainSupport(t *testing.T) {
+	baseErr := errors.New("root failed")
+	err := New(codeRoot, "root error", baseErr)
+	wrapped := fmt.Errorf("handler: %w", err)
+
+	assert.True(t, errors.Is(wrapped, baseErr), "errors.Is should reach the cause")
+	assert.True(t, CheckCode(wrapped, codeRoot), "CheckCode should walk the chain")
+
+	var pe PublicErr
+	require.True(t, errors.As(wrapped, &pe), "errors.As should find the PublicErr")
+	assert.Equal(t, "root error", pe.Public())
+}
+
+func TestIs_MatchesByCode(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", New(ErrNotFound, "user not found", nil))
+
+	assert.True(t, errors.Is(err, ErrNotFound), "errors.Is should match an ErrCode sentinel")
+	assert.False(t, errors.Is(err, ErrConflict), "errors.Is should not match a different code")
+	assert.True(t, errors.Is(err, New(ErrNotFound, "other message", nil)), "errors.Is should match a PublicErr with the same code")
+}
+
+func TestStackTrace_CapturedAtNew(t *testing.T) {
+	err := rootErr()
+
+	st, ok := err.(StackTracer)
+	require.True(t, ok, "merr errors should implement StackTracer")
+
+	frames := st.StackTrace()
+	require.NotEmpty(t, frames)
+	assert.True(t, strings.HasSuffix(frames[0].Function, ".rootErr"), "top frame should be the caller of New, got %s", frames[0].Function)
+	assert.True(t, strings.HasSuffix(frames[0].File, "merr_test.go"))
+	assert.NotZero(t, frames[0].Line)
+}
+
+func TestStackTrace_Disabled(t *testing.T) {
+	SetStackCapture(false)
+	defer SetStackCapture(true)
+
+	err := New(codeRoot, "root error", nil)
+	assert.Empty(t, err.(StackTracer).StackTrace(), "no stack should be captured when disabled")
+
+	SetStackCapture(true)
+	err = NewWithoutStack(codeRoot, "root error", nil)
+	assert.Empty(t, err.(StackTracer).StackTrace(), "NewWithoutStack should never capture a stack")
+}
+
+func TestFormat(t *testing.T) {
+	err := rootErr()
+
+	assert.Equal(t, "root failed", fmt.Sprintf("%v", err))
+	assert.Equal(t, "root failed", fmt.Sprintf("%s", err))
+
+	verbose := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(verbose, "E_ROOT: root error\n"), "unexpected %%+v output: %s", verbose)
+	assert.Contains(t, verbose, ".rootErr\n\t")
+	assert.Contains(t, verbose, "caused by: root failed")
+}
+
+func TestWith_AttachesFields(t *testing.T) {
+	base := New(ErrNotFound, "order not found", nil)
+	err := base.With("order_id", 42).With("user_id", "u-1")
+
+	assert.Equal(t, []Field{{Key: "order_id", Value: 42}, {Key: "user_id", Value: "u-1"}}, err.Fields())
+	assert.Empty(t, base.Fields(), "With should not modify the original error")
+	assert.Equal(t, "order not found", err.Public(), "fields should not leak into the public message")
+}
+
+func TestFields_MergesChain(t *testing.T) {
+	inner := New(ErrNotFound, "order not found", nil).With("order_id", 42).With("resource", "order")
+	err := WithField(fmt.Errorf("load: %w", inner), "resource", "invoice")
+
+	assert.Equal(t, []Field{{Key: "order_id", Value: 42}, {Key: "resource", Value: "invoice"}}, Fields(err))
+	assert.True(t, CheckCode(err, ErrNotFound), "WithField should keep the chain intact")
+	assert.Nil(t, WithField(nil, "k", "v"))
+}
+
+func TestWithField_PlainErrorStaysInternal(t *testing.T) {
+	err := WithField(errors.New("boom"), "user_id", 7)
+
+	_, ok := AsPublic(err)
+	assert.False(t, ok, "WithField should not turn a plain error into a PublicErr")
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, []Field{{Key: "user_id", Value: 7}}, Fields(err))
+}
+
+func TestWrap_PreservesCodeAndPublic(t *testing.T) {
+	inner := New(ErrNotFound, "user not found", errors.New("no rows"))
+	err := Wrap(Wrap(inner, "load profile"), "handle request")
+
+	pe, ok := AsPublic(err)
+	require.True(t, ok, "Wrap of a PublicErr should stay public")
+	assert.Equal(t, ErrNotFound, pe.Code())
+	assert.Equal(t, "user not found", pe.Public())
+	assert.Equal(t, "handle request: load profile: no rows", err.Error())
+	assert.True(t, errors.Is(err, inner), "the original error should stay reachable")
+}
+
+func TestWrap_PlainError(t *testing.T) {
+	base := errors.New("connection reset")
+	err := Wrap(base, "query users")
+
+	_, ok := AsPublic(err)
+	assert.False(t, ok, "Wrap of a plain error should not become public")
+	assert.Equal(t, "query users: connection reset", err.Error())
+	assert.True(t, errors.Is(err, base))
+	assert.NotEmpty(t, err.(StackTracer).StackTrace())
+	assert.Nil(t, Wrap(nil, "ignored"))
+}
+
+func TestWrapCode_Overrides(t *testing.T) {
+	inner := New(ErrNotFound, "user not found", nil)
+	err := WrapCode(inner, ErrPermissionDenied, "access denied")
+
+	assert.Equal(t, ErrPermissionDenied, err.Code())
+	assert.Equal(t, "access denied", err.Public())
+	assert.True(t, errors.Is(err, inner), "the original error should stay reachable")
+	assert.Nil(t, WrapCode(nil, ErrPermissionDenied, "ignored"))
+}
+
+// When a chain contains several PublicErrs, the outermost one decides the
+// code and public message. errors.Is still matches any code in the chain.
+func TestChainPrecedence(t *testing.T) {
+	inner := New(ErrNotFound, "user not found", nil)
+	recoded := WrapCode(inner, ErrPermissionDenied, "access denied")
+	err := fmt.Errorf("handler: %w", Wrap(recoded, "authorize"))
+
+	pe, ok := AsPublic(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrPermissionDenied, pe.Code(), "outermost code should win")
+	assert.Equal(t, "access denied", pe.Public(), "outermost public message should win")
+
+	assert.True(t, CheckCode(err, ErrPermissionDenied))
+	assert.False(t, CheckCode(err, ErrNotFound), "CheckCode only considers the outermost PublicErr")
+	assert.True(t, errors.Is(err, ErrNotFound), "errors.Is matches any code in the chain")
+}
+
+func TestWrap_Format(t *testing.T) {
+	err := Wrap(rootErr(), "handle request")
+
+	verbose := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(verbose, "handle request\n"), "unexpected %%+v output: %s", verbose)
+	assert.Contains(t, verbose, "caused by: E_ROOT: root error")
+	assert.Contains(t, verbose, "caused by: root failed")
+}
+
+func TestWithMeta(t *testing.T) {
+	base := New(ErrNotFound, "order not found", nil).WithMeta("resource", "order")
+	err := Wrap(base.WithMeta("resource", "invoice").WithMeta("id", "o-1"), "load")
+
+	assert.Equal(t, map[string]string{"resource": "invoice", "id": "o-1"}, Metadata(err))
+	assert.Equal(t, map[string]string{"resource": "order"}, Metadata(base), "WithMeta should not modify the original error")
+	assert.Nil(t, Metadata(errors.New("plain")))
+}
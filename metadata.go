@@ -0,0 +1,23 @@
+package merr
+
+// Metadata returns the public metadata attached across target's chain.
+// When the same key appears more than once, the outermost value wins.
+// It returns nil if no metadata is attached.
+func Metadata(target error) map[string]string {
+	var meta map[string]string
+	walk(target, func(e error) {
+		me, ok := e.(*err)
+		if !ok {
+			return
+		}
+		for k, v := range me.meta {
+			if meta == nil {
+				meta = make(map[string]string)
+			}
+			if _, exists := meta[k]; !exists {
+				meta[k] = v
+			}
+		}
+	})
+	return meta
+}
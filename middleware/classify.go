@@ -0,0 +1,16 @@
+package merrmid
+
+import "github.com/mandacode-com/merr"
+
+// classifyError turns a non-public error recognized by merr.Classify into a
+// public error titled after its code. Other errors are returned unchanged.
+func classifyError(err error) error {
+	if _, ok := merr.AsPublic(err); ok {
+		return err
+	}
+	code := merr.Classify(err)
+	if code == "" || code == merr.ErrUnknown {
+		return err
+	}
+	return merr.WrapCode(err, code, code.Title())
+}
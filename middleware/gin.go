@@ -1,7 +1,7 @@
 package merrmid
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mandacode-com/merr"
@@ -9,25 +9,78 @@ import (
 
 // ErrorResponse represents the JSON structure for error responses
 type ErrorResponse struct {
-	Error string         `json:"error"`
-	Code  merr.ErrCode   `json:"code"`
+	Error string       `json:"error"`
+	Code  merr.ErrCode `json:"code"`
+	// Errors lists every public error when the response aggregates several
+	Errors []ErrorDetail `json:"errors,omitempty"`
+	// Violations lists the invalid fields of validation errors
+	Violations []merr.Violation `json:"violations,omitempty"`
+	// Metadata holds the public metadata attached to the error
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Template is the template the public message was rendered from
+	Template string `json:"template,omitempty"`
+	// Params holds the sanitized parameters of the template
+	Params map[string]any `json:"params,omitempty"`
+	// RequestID identifies the request in the server logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrorDetail represents a single public error in an aggregated response
+type ErrorDetail struct {
+	Error    string         `json:"error"`
+	Code     merr.ErrCode   `json:"code"`
+	Template string         `json:"template,omitempty"`
+	Params   map[string]any `json:"params,omitempty"`
 }
 
 // GinErrorHandler is a Gin middleware that handles errors and converts them to JSON responses.
-// It processes all errors in the context and returns the first merr.PublicErr found,
-// or a generic internal server error if no public errors are found.
+// It processes all errors in the context and aggregates every merr.PublicErr found,
+// or returns a generic internal server error if no public errors are found.
+// Call RegisterJSONTagName for binding violations to report json keys.
 func GinErrorHandler() gin.HandlerFunc {
 	return GinErrorHandlerWithOptions(nil)
 }
 
 // GinErrorHandlerOptions provides configuration options for the error handler
 type GinErrorHandlerOptions struct {
-	// LogErrors determines whether to log internal errors (default: true)
+	// LogErrors determines whether to log public and internal errors (default: true)
 	LogErrors bool
+	// Logger receives the error logs (default: slog.Default())
+	Logger *slog.Logger
+	// LogLevel returns the level of errors rendered with the given status (default: DefaultHTTPLogLevel)
+	LogLevel func(status int) slog.Level
 	// CustomErrorResponse allows customizing the error response format
 	CustomErrorResponse func(c *gin.Context, publicErr merr.PublicErr)
 	// OnInternalError is called when a non-public error occurs
 	OnInternalError func(c *gin.Context, err error)
+	// CodePolicy derives the response code when several public errors occur (default: merr.FirstCode).
+	// Use the policies of Mapper, e.g. Mapper.HighestStatusCode(), to rank codes with its statuses
+	CodePolicy merr.CodePolicy
+	// ProblemDetails renders errors as RFC 9457 application/problem+json instead of ErrorResponse
+	ProblemDetails bool
+	// ProblemTypes maps error codes to problem type URIs and titles
+	ProblemTypes map[merr.ErrCode]ProblemType
+	// ProblemTypeBaseURI builds the type URI of unregistered codes as base + code (default: "about:blank")
+	ProblemTypeBaseURI string
+	// ProblemInstance returns the instance member of problem details (default: the request path)
+	ProblemInstance func(c *gin.Context) string
+	// Mapper maps error codes to HTTP statuses (default: the merr registry)
+	Mapper *merr.Mapper
+	// Translator localizes public messages into the locales negotiated from Accept-Language
+	Translator merr.Translator
+	// DefaultLocale is tried when no Accept-Language locale has a translation
+	DefaultLocale string
+	// ClassifyErrors converts non-public errors recognized by merr.Classify,
+	// such as context.DeadlineExceeded or sql.ErrNoRows, into public errors
+	ClassifyErrors bool
+	// RecoverPanics converts panics into internal errors, handled like
+	// non-public errors, except http.ErrAbortHandler, which is re-panicked (default: false)
+	RecoverPanics bool
+	// RequestIDHeader is the header carrying the request ID reported with errors,
+	// echoed in the response (default: DefaultRequestIDHeader)
+	RequestIDHeader string
+	// GenerateRequestID creates the ID of requests without one (default: random hex IDs)
+	GenerateRequestID func() string
 }
 
 // GinErrorHandlerWithOptions creates a Gin error handler with custom options
@@ -39,55 +92,95 @@ func GinErrorHandlerWithOptions(opts *GinErrorHandlerOptions) gin.HandlerFunc {
 	}
 
 	return func(c *gin.Context) {
-		c.Next()
+		var panicErr error
+		func() {
+			if opts.RecoverPanics {
+				defer recoverHTTPPanic(&panicErr)
+			}
+			c.Next()
+		}()
 
-		if len(c.Errors) == 0 {
+		if panicErr != nil {
+			c.Abort()
+			writeInternalError(c, opts, panicErr)
 			return
 		}
 
-		// Process errors and find the first public error
-		var publicErr merr.PublicErr
-		var internalErr error
+		if len(c.Errors) == 0 {
+			return
+		}
 
+		errs := make([]error, 0, len(c.Errors))
 		for _, ginErr := range c.Errors {
-			if pe, ok := ginErr.Err.(merr.PublicErr); ok {
-				if publicErr == nil {
-					publicErr = pe
-				}
-			} else if internalErr == nil {
-				internalErr = ginErr.Err
-			}
+			errs = append(errs, ginErr.Err)
 		}
+		pe, internalErr := collectErrors(errs, opts.ClassifyErrors, opts.CodePolicy)
 
-		// If we found a public error, use it
-		if publicErr != nil {
+		if pe != nil {
+			opts.httpLog(c).log(c.Request, "public error", opts.Mapper.ToHTTPStatus(pe.Code()), pe.Code(), pe)
 			if opts.CustomErrorResponse != nil {
-				opts.CustomErrorResponse(c, publicErr)
+				opts.CustomErrorResponse(c, pe)
 			} else {
-				c.JSON(publicErr.Code().ToHTTPStatus(), ErrorResponse{
-					Error: publicErr.Public(),
-					Code:  publicErr.Code(),
-				})
+				writeError(c, opts, pe)
 			}
 			return
 		}
 
 		// Handle internal error
 		if internalErr != nil {
-			if opts.LogErrors {
-				log.Printf("Internal error: %v", internalErr)
-			}
-			
-			if opts.OnInternalError != nil {
-				opts.OnInternalError(c, internalErr)
-			} else {
-				c.JSON(merr.ErrInternalServerError.ToHTTPStatus(), ErrorResponse{
-					Error: "Internal server error",
-					Code:  merr.ErrInternalServerError,
-				})
-			}
+			writeInternalError(c, opts, internalErr)
+		}
+	}
+}
+
+// writeInternalError logs a non-public error and reports it to the client
+// as an internal server error
+func writeInternalError(c *gin.Context, opts *GinErrorHandlerOptions, err error) {
+	code := internalServerError.Code()
+	opts.httpLog(c).log(c.Request, "internal error", opts.Mapper.ToHTTPStatus(code), code, err)
+
+	if opts.OnInternalError != nil {
+		opts.OnInternalError(c, err)
+	} else {
+		writeError(c, opts, internalServerError)
+	}
+}
+
+// httpLog returns where and how errors of the request are logged
+func (opts *GinErrorHandlerOptions) httpLog(c *gin.Context) httpLog {
+	return httpLog{
+		enabled: opts.LogErrors,
+		logger:  opts.Logger,
+		level:   opts.LogLevel,
+		route:   c.FullPath(),
+		reqID:   opts.requestID(c),
+	}
+}
+
+// requestID returns the ID reported with the errors of the request
+func (opts *GinErrorHandlerOptions) requestID(c *gin.Context) string {
+	return httpRequestID(c.Request, c.Writer.Header(), opts.RequestIDHeader, opts.GenerateRequestID)
+}
+
+// writeError renders a public error in the configured format
+func writeError(c *gin.Context, opts *GinErrorHandlerOptions, publicErr merr.PublicErr) {
+	ro := renderOptions{
+		ProblemDetails:     opts.ProblemDetails,
+		ProblemTypes:       opts.ProblemTypes,
+		ProblemTypeBaseURI: opts.ProblemTypeBaseURI,
+		Mapper:             opts.Mapper,
+		Translator:         opts.Translator,
+		DefaultLocale:      opts.DefaultLocale,
+		RequestID:          opts.requestID(c),
+	}
+	instance := func() string {
+		if opts.ProblemInstance != nil {
+			return opts.ProblemInstance(c)
 		}
+		return c.Request.URL.Path
 	}
+	status, body := renderError(c.Writer.Header(), c.Request, ro, instance, publicErr)
+	c.JSON(status, body)
 }
 
 // AbortWithError is a helper function to abort with a merr.PublicErr
@@ -1,11 +1,19 @@
 package merrmid
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mandacode-com/merr"
@@ -103,4 +111,658 @@ func TestAbortWithPublicError(t *testing.T) {
 	
 	assert.Equal(t, "Invalid input provided", response.Error)
 	assert.Equal(t, merr.ErrInvalidInput, response.Code)
-}
\ No newline at end of file
+}
+func TestGinErrorHandler_WrappedPublicError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		publicErr := merr.New(merr.ErrConflict, "Already exists", nil)
+		c.Error(fmt.Errorf("create user: %w", publicErr))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Already exists", response.Error)
+	assert.Equal(t, merr.ErrConflict, response.Code)
+}
+
+func TestGinErrorHandler_FieldsLoggedNotExposed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/public", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil).
+			With("order_id", "o-123").
+			WithMeta("resource", "order"))
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		c.Error(merr.WithField(errors.New("db down"), "order_id", "o-456"))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/public", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), "o-123", "fields must not appear in the response")
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]string{"resource": "order"}, response.Metadata, "public metadata should appear in the response")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/internal", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "o-456", "fields must not appear in the response")
+	assert.Contains(t, logs.String(), "order_id=o-456", "fields should be logged")
+}
+
+func TestGinErrorHandler_MultiplePublicErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		CodePolicy: merr.HighestStatusCode,
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Item 1 not found", nil))
+		c.Error(errors.New("ignored internal error"))
+		c.Error(merr.New(merr.ErrConflict, "Item 2 already exists", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, merr.ErrConflict, response.Code)
+	assert.Equal(t, []ErrorDetail{
+		{Error: "Item 1 not found", Code: merr.ErrNotFound},
+		{Error: "Item 2 already exists", Code: merr.ErrConflict},
+	}, response.Errors)
+}
+
+func TestGinErrorHandler_JoinedPublicError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.Join(
+			merr.New(merr.ErrInvalidInput, "Row 1 is invalid", nil),
+			merr.New(merr.ErrInvalidInput, "Row 7 is invalid", nil),
+		))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Row 1 is invalid; Row 7 is invalid", response.Error)
+	assert.Len(t, response.Errors, 2)
+}
+
+func TestGinErrorHandler_BindValidationErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	RegisterJSONTagName()
+
+	type signupRequest struct {
+		Email string `json:"email" binding:"required,email"`
+		Age   int    `json:"age" binding:"gte=18"`
+	}
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.POST("/signup", func(c *gin.Context) {
+		var req signupRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			AbortWithError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/signup", strings.NewReader(`{"email":"nope","age":12}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, merr.ErrInvalidInput, response.Code)
+	assert.Equal(t, "Invalid input", response.Error)
+	assert.Equal(t, []merr.Violation{
+		{Field: "email", Rule: "email", Message: "email failed on the 'email' rule"},
+		{Field: "age", Rule: "gte", Message: "age failed on the 'gte=18' rule"},
+	}, response.Violations)
+}
+
+func TestGinErrorHandler_ValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		v := merr.NewValidationError(merr.ErrUnprocessableEntity, "Cannot place order")
+		v.Add("quantity", "stock", "only 3 items left")
+		c.Error(v.Err())
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, []merr.Violation{
+		{Field: "quantity", Rule: "stock", Message: "only 3 items left"},
+	}, response.Violations)
+}
+
+func TestGinErrorHandler_ProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		ProblemDetails: true,
+		ProblemTypes: map[merr.ErrCode]ProblemType{
+			merr.ErrNotFound: {Type: "https://errors.example.com/not-found", Title: "Resource not found"},
+		},
+	}))
+
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "User not found", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/42", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem ProblemDetails
+	err := json.Unmarshal(w.Body.Bytes(), &problem)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://errors.example.com/not-found", problem.Type)
+	assert.Equal(t, "Resource not found", problem.Title)
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "User not found", problem.Detail)
+	assert.Equal(t, "/users/42", problem.Instance)
+	assert.Equal(t, map[string]any{"code": "not_found", "request_id": "req-42"}, problem.Extensions)
+}
+
+func TestGinErrorHandler_ProblemDetailsDefaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		ProblemDetails:     true,
+		ProblemTypeBaseURI: "https://errors.example.com/",
+		ProblemInstance: func(c *gin.Context) string {
+			return "urn:request:" + c.GetHeader("X-Request-ID")
+		},
+	}))
+
+	r.GET("/validate", func(c *gin.Context) {
+		c.Error((&merr.ValidationError{}).Add("email", "required", "email is required").Err())
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		c.Error(errors.New("db down"))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/validate", nil)
+	req.Header.Set("X-Request-ID", "abc")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var problem ProblemDetails
+	err := json.Unmarshal(w.Body.Bytes(), &problem)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://errors.example.com/invalid_input", problem.Type)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, "urn:request:abc", problem.Instance)
+	assert.Equal(t, "invalid_input", problem.Extensions["code"])
+	assert.Len(t, problem.Extensions["violations"], 1)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/internal", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+	err = json.Unmarshal(w.Body.Bytes(), &problem)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://errors.example.com/internal_server_error", problem.Type)
+	assert.Equal(t, "Internal Server Error", problem.Title)
+	assert.Equal(t, "Internal server error", problem.Detail)
+}
+
+func TestGinErrorHandler_Mapper(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		Mapper: merr.NewMapper().WithHTTPStatus(merr.ErrConflict, http.StatusPreconditionFailed),
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrConflict, "Version mismatch", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	var response ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, merr.ErrConflict, response.Code)
+}
+
+func TestGinErrorHandler_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	catalog := merr.NewMessageCatalog()
+	catalog.Add("en", map[string]string{"order.not_found": "Order {id} was not found"})
+	catalog.Add("ko", map[string]string{"order.not_found": "주문 {id}을(를) 찾을 수 없습니다"})
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		Translator:    catalog,
+		DefaultLocale: "en",
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil).
+			WithMessageKey("order.not_found", map[string]any{"id": "o-1"}))
+	})
+
+	tests := []struct {
+		acceptLanguage string
+		locale         string
+		message        string
+	}{
+		{"ko-KR,ko;q=0.9,en;q=0.8", "ko-KR", "주문 o-1을(를) 찾을 수 없습니다"},
+		{"fr, en;q=0.5", "en", "Order o-1 was not found"},
+		{"fr", "en", "Order o-1 was not found"},
+		{"", "en", "Order o-1 was not found"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		r.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, tt.message, response.Error, tt.acceptLanguage)
+		assert.Equal(t, tt.locale, w.Header().Get("Content-Language"), tt.acceptLanguage)
+	}
+}
+
+func TestGinErrorHandler_LocalizedProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	catalog := merr.NewMessageCatalog()
+	catalog.Add("ko", map[string]string{
+		"order.not_found": "주문을 찾을 수 없습니다",
+		"user.not_found":  "사용자를 찾을 수 없습니다",
+	})
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		Translator:     catalog,
+		ProblemDetails: true,
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil).WithMessageKey("order.not_found", nil))
+		c.Error(merr.New(merr.ErrNotFound, "User not found", nil).WithMessageKey("user.not_found", nil))
+		c.Error(merr.New(merr.ErrConflict, "Version mismatch", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.Header.Set("Accept-Language", "ko")
+	r.ServeHTTP(w, req)
+
+	var problem ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	assert.Equal(t, "ko", w.Header().Get("Content-Language"))
+	assert.Equal(t, "주문을 찾을 수 없습니다; 사용자를 찾을 수 없습니다; Version mismatch", problem.Detail)
+	errs, ok := problem.Extensions["errors"].([]any)
+	require.True(t, ok)
+	require.Len(t, errs, 3)
+	assert.Equal(t, "사용자를 찾을 수 없습니다", errs[1].(map[string]any)["error"])
+	assert.Equal(t, "Version mismatch", errs[2].(map[string]any)["error"], "errors without a key stay untranslated")
+}
+
+func TestGinErrorHandler_TemplatedError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.Newf(merr.ErrNotFound, "Order {id} was not found", map[string]any{"id": "<o-1>"}, nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.Equal(t, "Order <o-1> was not found", response.Error)
+	assert.Equal(t, "Order {id} was not found", response.Template)
+	assert.Equal(t, map[string]any{"id": "<o-1>"}, response.Params)
+	assert.NotContains(t, w.Body.String(), "<o-1>", "HTML should be escaped in JSON")
+}
+
+func TestGinErrorHandler_RetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrTooManyRequests, "Slow down", nil).RetryAfter(1500 * time.Millisecond))
+	})
+	r.GET("/nohint", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrTooManyRequests, "Slow down", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"), "delays should round up to whole seconds")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/nohint", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Retry-After"))
+}
+
+func TestGinErrorHandler_ClassifyErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{ClassifyErrors: true}))
+
+	r.GET("/timeout", func(c *gin.Context) {
+		c.Error(fmt.Errorf("query orders: %w", context.DeadlineExceeded))
+	})
+	r.GET("/other", func(c *gin.Context) {
+		c.Error(errors.New("boom"))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/timeout", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, merr.ErrTimeout, response.Code)
+	assert.Equal(t, "Timeout", response.Error)
+	assert.NotContains(t, w.Body.String(), "query orders")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/other", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestGinErrorHandler_RecoverPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var internal error
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		RecoverPanics: true,
+		OnInternalError: func(c *gin.Context, err error) {
+			internal = err
+			c.JSON(http.StatusInternalServerError, newErrorResponse(internalServerError, nil))
+		},
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		panic("nil map")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Internal server error", response.Error)
+	assert.Equal(t, merr.ErrInternalServerError, response.Code)
+
+	require.Error(t, internal)
+	assert.Equal(t, "panic: nil map", internal.Error())
+	assert.True(t, merr.CheckCode(internal, merr.ErrInternalServerError))
+	var st merr.StackTracer
+	require.ErrorAs(t, internal, &st)
+	assert.Contains(t, fmt.Sprintf("%+v", internal), "TestGinErrorHandler_RecoverPanics", "the stack should include the panicking handler")
+}
+
+func TestGinErrorHandler_RecoverPanicsErrAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{RecoverPanics: true}))
+
+	r.GET("/test", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { r.ServeHTTP(w, req) })
+}
+
+func TestGinErrorHandler_Logger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		LogErrors: true,
+		Logger:    slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}))
+
+	r.GET("/orders/:id", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil).With("order_id", c.Param("id")))
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		c.Error(merr.Wrap(errors.New("db down"), "load orders"))
+	})
+
+	for _, path := range []string{"/orders/o-1", "/internal"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var public map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &public))
+	assert.Equal(t, "WARN", public["level"])
+	assert.Equal(t, "public error", public["msg"])
+	assert.Equal(t, "GET", public["method"])
+	assert.Equal(t, "/orders/:id", public["route"])
+	assert.Equal(t, "/orders/o-1", public["path"])
+	assert.Equal(t, float64(http.StatusNotFound), public["status"])
+	assert.Equal(t, "not_found", public["code"])
+	assert.Equal(t, map[string]any{"order_id": "o-1"}, public["fields"])
+	assert.Contains(t, public["stack"], "TestGinErrorHandler_Logger")
+	logged, ok := public["error"].(map[string]any)
+	require.True(t, ok, "merr errors should be logged as a group")
+	assert.Equal(t, "Order not found", logged["public"])
+
+	var internal map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &internal))
+	assert.Equal(t, "ERROR", internal["level"])
+	assert.Equal(t, "internal error", internal["msg"])
+	assert.Equal(t, "internal_server_error", internal["code"])
+	logged, ok = internal["error"].(map[string]any)
+	require.True(t, ok, "merr errors should be logged as a group")
+	assert.Equal(t, "load orders", logged["msg"])
+	assert.Equal(t, "db down", logged["cause"])
+	assert.Equal(t, float64(http.StatusInternalServerError), internal["status"])
+}
+
+func TestGinErrorHandler_LogLevel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		LogErrors: true,
+		Logger:    slog.New(slog.NewTextHandler(&logs, nil)),
+		LogLevel: func(status int) slog.Level {
+			if status < 500 {
+				return slog.LevelDebug
+			}
+			return slog.LevelError
+		},
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil))
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Empty(t, logs.String(), "4xx errors should be logged below the handler level")
+}
+
+func TestGinErrorHandler_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	r := gin.New()
+	r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{
+		LogErrors:         true,
+		Logger:            slog.New(slog.NewJSONHandler(&logs, nil)),
+		RequestIDHeader:   "X-Correlation-ID",
+		GenerateRequestID: func() string { return "generated" },
+	}))
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(errors.New("db down"))
+	})
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"corr-1", "corr-1"},
+		{"", "generated"},
+		{"bad id\n", "generated"},
+		{strings.Repeat("x", 200), "generated"},
+	}
+	for _, tt := range tests {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set("X-Correlation-ID", tt.header)
+		r.ServeHTTP(w, req)
+
+		var response ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, tt.want, response.RequestID)
+		assert.Equal(t, tt.want, w.Header().Get("X-Correlation-ID"), "the ID should be echoed in the response")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+		assert.Equal(t, tt.want, record["request_id"])
+	}
+}
+
+func TestGinErrorHandler_RequestIDFromEarlierMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Header("X-Request-ID", "set-by-middleware")
+		c.Next()
+	})
+	r.Use(GinErrorHandler())
+
+	r.GET("/test", func(c *gin.Context) {
+		c.Error(merr.New(merr.ErrNotFound, "Order not found", nil))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "set-by-middleware", response.RequestID)
+}
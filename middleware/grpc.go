@@ -2,14 +2,24 @@ package merrmid
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 
 	"github.com/mandacode-com/merr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
+// MessageMetadataKey is the errdetails.ErrorInfo metadata key holding the
+// public message of each member of an aggregated error. It is reserved so
+// that it does not collide with the metadata set with WithMeta.
+const MessageMetadataKey = "merr_message"
+
 // GRPCErrorInterceptor is a gRPC middleware that intercepts errors and converts them to gRPC status errors.
 func GRPCErrorInterceptor() grpc.UnaryServerInterceptor {
 	return GRPCErrorInterceptorWithOptions(nil)
@@ -17,12 +27,38 @@ func GRPCErrorInterceptor() grpc.UnaryServerInterceptor {
 
 // GRPCErrorInterceptorOptions provides configuration options for the gRPC error interceptor
 type GRPCErrorInterceptorOptions struct {
-	// LogErrors determines whether to log internal errors (default: true)
+	// LogErrors determines whether to log public and internal errors (default: true)
 	LogErrors bool
+	// Logger receives the error logs (default: slog.Default())
+	Logger *slog.Logger
+	// LogLevel returns the level of errors returned with the given code (default: DefaultGRPCLogLevel)
+	LogLevel func(code codes.Code) slog.Level
 	// OnInternalError is called when a non-public error occurs
 	OnInternalError func(ctx context.Context, err error) error
 	// OnPublicError is called when a public error occurs, allows customization
 	OnPublicError func(ctx context.Context, publicErr merr.PublicErr) error
+	// Domain is the logical grouping reported in errdetails.ErrorInfo, e.g. "orders.example.com"
+	Domain string
+	// Mapper maps error codes to gRPC codes (default: the merr registry)
+	Mapper *merr.Mapper
+	// Translator localizes public messages into the locale read from the incoming metadata
+	Translator merr.Translator
+	// DefaultLocale is tried when the requested locale has no translation
+	DefaultLocale string
+	// LocaleMetadataKey is the metadata key holding the requested locales,
+	// in Accept-Language syntax (default: "accept-language")
+	LocaleMetadataKey string
+	// ClassifyErrors converts non-public errors recognized by merr.Classify,
+	// such as context.DeadlineExceeded or sql.ErrNoRows, into public errors
+	ClassifyErrors bool
+	// RecoverPanics converts panics into internal errors, handled like
+	// non-public errors (default: false)
+	RecoverPanics bool
+	// RequestIDMetadataKey is the metadata key carrying the request ID reported
+	// with errors (default: DefaultRequestIDMetadataKey)
+	RequestIDMetadataKey string
+	// GenerateRequestID creates the ID of requests without one (default: random hex IDs)
+	GenerateRequestID func() string
 }
 
 // GRPCErrorInterceptorWithOptions creates a gRPC error interceptor with custom options
@@ -39,29 +75,38 @@ func GRPCErrorInterceptorWithOptions(opts *GRPCErrorInterceptorOptions) grpc.Una
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (any, error) {
-		resp, err := handler(ctx, req)
+		var panicErr error
+		resp, err := func() (any, error) {
+			if opts.RecoverPanics {
+				defer recoverPanic(&panicErr)
+			}
+			return handler(ctx, req)
+		}()
+		if panicErr != nil {
+			err = panicErr
+		}
 		if err == nil {
 			return resp, nil
 		}
+		ctx = opts.withRequestID(ctx)
+		if opts.ClassifyErrors {
+			err = classifyError(err)
+		}
 
-		// Handle merr.PublicErr
-		if publicErr, ok := err.(merr.PublicErr); ok {
+		// Handle merr.PublicErr; recovered panics are internal errors
+		if publicErr, ok := merr.AsPublic(err); ok && panicErr == nil {
+			opts.logPublicError(ctx, info.FullMethod, publicErr)
 			if opts.OnPublicError != nil {
 				if customErr := opts.OnPublicError(ctx, publicErr); customErr != nil {
 					return nil, customErr
 				}
 			}
-			
-			return nil, status.Errorf(
-				publicErr.Code().ToGRPCCode(),
-				"%s", publicErr.Public(),
-			)
+
+			return nil, publicStatus(ctx, publicErr, opts)
 		}
 
 		// Handle other errors
-		if opts.LogErrors {
-			log.Printf("gRPC internal error in %s: %v", info.FullMethod, err)
-		}
+		opts.logInternalError(ctx, info.FullMethod, err)
 
 		if opts.OnInternalError != nil {
 			if customErr := opts.OnInternalError(ctx, err); customErr != nil {
@@ -75,7 +120,7 @@ func GRPCErrorInterceptorWithOptions(opts *GRPCErrorInterceptorOptions) grpc.Una
 		}
 
 		// Convert to internal gRPC error
-		return nil, status.Errorf(codes.Internal, "Internal server error")
+		return nil, internalStatus(ctx)
 	}
 }
 
@@ -98,32 +143,41 @@ func GRPCStreamErrorInterceptorWithOptions(opts *GRPCErrorInterceptorOptions) gr
 		info *grpc.StreamServerInfo,
 		handler grpc.StreamHandler,
 	) error {
-		err := handler(srv, stream)
+		var panicErr error
+		err := func() error {
+			if opts.RecoverPanics {
+				defer recoverPanic(&panicErr)
+			}
+			return handler(srv, stream)
+		}()
+		if panicErr != nil {
+			err = panicErr
+		}
 		if err == nil {
 			return nil
 		}
+		ctx := opts.withRequestID(stream.Context())
+		if opts.ClassifyErrors {
+			err = classifyError(err)
+		}
 
-		// Handle merr.PublicErr
-		if publicErr, ok := err.(merr.PublicErr); ok {
+		// Handle merr.PublicErr; recovered panics are internal errors
+		if publicErr, ok := merr.AsPublic(err); ok && panicErr == nil {
+			opts.logPublicError(ctx, info.FullMethod, publicErr)
 			if opts.OnPublicError != nil {
-				if customErr := opts.OnPublicError(stream.Context(), publicErr); customErr != nil {
+				if customErr := opts.OnPublicError(ctx, publicErr); customErr != nil {
 					return customErr
 				}
 			}
-			
-			return status.Errorf(
-				publicErr.Code().ToGRPCCode(),
-				"%s", publicErr.Public(),
-			)
+
+			return publicStatus(ctx, publicErr, opts)
 		}
 
 		// Handle other errors
-		if opts.LogErrors {
-			log.Printf("gRPC stream internal error in %s: %v", info.FullMethod, err)
-		}
+		opts.logInternalError(ctx, info.FullMethod, err)
 
 		if opts.OnInternalError != nil {
-			if customErr := opts.OnInternalError(stream.Context(), err); customErr != nil {
+			if customErr := opts.OnInternalError(ctx, err); customErr != nil {
 				return customErr
 			}
 		}
@@ -134,7 +188,152 @@ func GRPCStreamErrorInterceptorWithOptions(opts *GRPCErrorInterceptorOptions) gr
 		}
 
 		// Convert to internal gRPC error
-		return status.Errorf(codes.Internal, "Internal server error")
+		return internalStatus(ctx)
+	}
+}
+
+// logPublicError logs a public error returned by method
+func (opts *GRPCErrorInterceptorOptions) logPublicError(ctx context.Context, method string, publicErr merr.PublicErr) {
+	opts.logError(ctx, method, "public error", opts.Mapper.ToGRPCCode(publicErr.Code()), publicErr.Code(), publicErr)
+}
+
+// logInternalError logs a non-public error returned by method
+func (opts *GRPCErrorInterceptorOptions) logInternalError(ctx context.Context, method string, err error) {
+	grpcCode, code := codes.Internal, merr.ErrInternalServerError
+	if st, ok := status.FromError(err); ok {
+		grpcCode, code = st.Code(), merr.FromGRPCCode(st.Code())
+	}
+	opts.logError(ctx, method, "internal error", grpcCode, code, err)
+}
+
+// logError logs an error returned by method with the given codes
+func (opts *GRPCErrorInterceptorOptions) logError(ctx context.Context, method, msg string, grpcCode codes.Code, code merr.ErrCode, err error) {
+	if !opts.LogErrors {
+		return
+	}
+	level := DefaultGRPCLogLevel
+	if opts.LogLevel != nil {
+		level = opts.LogLevel
+	}
+	logError(ctx, opts.Logger, level(grpcCode), msg, code, err,
+		slog.String("method", method),
+		slog.String("grpc_code", grpcCode.String()),
+		slog.String("request_id", RequestID(ctx)),
+	)
+}
+
+// publicStatus converts a public error into a gRPC status error.
+// The merr code is preserved as the reason of an errdetails.ErrorInfo,
+// one per public error for aggregated errors, and validation errors
+// carry an errdetails.BadRequest. Translated messages are also attached
+// as an errdetails.LocalizedMessage, retry hints as an errdetails.RetryInfo
+// and the request ID as an errdetails.RequestInfo.
+func publicStatus(ctx context.Context, publicErr merr.PublicErr, opts *GRPCErrorInterceptorOptions) error {
+	loc := newLocalizer(opts.Translator, incomingLocales(ctx, opts), opts.DefaultLocale)
+	msg, locale := loc.message(publicErr)
+	st := status.New(opts.Mapper.ToGRPCCode(publicErr.Code()), msg)
+
+	var details []protoadapt.MessageV1
+	var multi *merr.Multi
+	if errors.As(publicErr, &multi) {
+		// The status message joins every public message, so each member
+		// keeps its own in the metadata.
+		for _, pe := range multi.PublicErrors() {
+			info := newErrorInfo(pe, opts)
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string)
+			}
+			info.Metadata[MessageMetadataKey], _ = loc.message(pe)
+			details = append(details, info)
+		}
+	} else {
+		details = append(details, newErrorInfo(publicErr, opts))
+	}
+	if violations := merr.Violations(publicErr); len(violations) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, v := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Field,
+				Reason:      v.Rule,
+				Description: v.Message,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	if delay, ok := merr.RetryAfter(publicErr); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	}
+	if id := RequestID(ctx); id != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: id})
+	}
+	if locale != "" {
+		details = append(details, &errdetails.LocalizedMessage{Locale: locale, Message: msg})
+	}
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// internalStatus is the status error reported for non-public errors,
+// carrying the request ID as an errdetails.RequestInfo
+func internalStatus(ctx context.Context) error {
+	st := status.New(codes.Internal, "Internal server error")
+	if id := RequestID(ctx); id != "" {
+		if withDetails, err := st.WithDetails(&errdetails.RequestInfo{RequestId: id}); err == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// withRequestID attaches the request ID of the incoming metadata, or a
+// generated one, to ctx and sends it back in the trailer
+func (opts *GRPCErrorInterceptorOptions) withRequestID(ctx context.Context) context.Context {
+	key := opts.RequestIDMetadataKey
+	if key == "" {
+		key = DefaultRequestIDMetadataKey
+	}
+	id := grpcRequestID(ctx, key, opts.GenerateRequestID)
+	// Fails outside of a server transport, e.g. in tests
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(key, id))
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID the gRPC interceptors attached to the
+// context given to OnPublicError and OnInternalError, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// incomingLocales returns the locales requested in the incoming metadata
+func incomingLocales(ctx context.Context, opts *GRPCErrorInterceptorOptions) []string {
+	key := opts.LocaleMetadataKey
+	if key == "" {
+		key = "accept-language"
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	var locales []string
+	for _, v := range md.Get(key) {
+		locales = append(locales, parseAcceptLanguage(v)...)
+	}
+	return locales
+}
+
+// newErrorInfo builds the errdetails.ErrorInfo of a public error
+func newErrorInfo(publicErr merr.PublicErr, opts *GRPCErrorInterceptorOptions) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason:   string(publicErr.Code()),
+		Domain:   opts.Domain,
+		Metadata: merr.Metadata(publicErr),
 	}
 }
 
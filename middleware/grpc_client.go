@@ -0,0 +1,218 @@
+package merrmid
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mandacode-com/merr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCClientErrorInterceptor is a gRPC client middleware that converts status errors into merr errors.
+func GRPCClientErrorInterceptor() grpc.UnaryClientInterceptor {
+	return GRPCClientErrorInterceptorWithOptions(nil)
+}
+
+// GRPCClientErrorInterceptorOptions provides configuration options for the gRPC client error interceptors
+type GRPCClientErrorInterceptorOptions struct {
+	// Domain is the errdetails.ErrorInfo domain of the trusted servers, e.g. "orders.example.com".
+	// Reasons from other domains are only used when they are registered codes
+	Domain string
+	// Mapper maps error codes to gRPC codes when picking the code of aggregated errors
+	// (default: the merr registry)
+	Mapper *merr.Mapper
+}
+
+// GRPCClientErrorInterceptorWithOptions creates a gRPC client error interceptor with custom options
+func GRPCClientErrorInterceptorWithOptions(opts *GRPCClientErrorInterceptorOptions) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		callOpts ...grpc.CallOption,
+	) error {
+		return FromStatusErrorWithOptions(invoker(ctx, method, req, reply, cc, callOpts...), opts)
+	}
+}
+
+// GRPCStreamClientErrorInterceptor converts status errors into merr errors for streaming calls
+func GRPCStreamClientErrorInterceptor() grpc.StreamClientInterceptor {
+	return GRPCStreamClientErrorInterceptorWithOptions(nil)
+}
+
+// GRPCStreamClientErrorInterceptorWithOptions creates a gRPC stream client error interceptor with custom options
+func GRPCStreamClientErrorInterceptorWithOptions(opts *GRPCClientErrorInterceptorOptions) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		callOpts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			return nil, FromStatusErrorWithOptions(err, opts)
+		}
+		return &clientStream{ClientStream: cs, opts: opts}, nil
+	}
+}
+
+// clientStream converts the errors returned by the wrapped stream
+type clientStream struct {
+	grpc.ClientStream
+	opts *GRPCClientErrorInterceptorOptions
+}
+
+func (s *clientStream) Header() (metadata.MD, error) {
+	md, err := s.ClientStream.Header()
+	return md, FromStatusErrorWithOptions(err, s.opts)
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return FromStatusErrorWithOptions(s.ClientStream.SendMsg(m), s.opts)
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return FromStatusErrorWithOptions(s.ClientStream.RecvMsg(m), s.opts)
+}
+
+// FromStatusError converts a gRPC status error into a merr error, trusting
+// only the ErrorInfo reasons that are registered codes.
+// See FromStatusErrorWithOptions.
+func FromStatusError(err error) error {
+	return FromStatusErrorWithOptions(err, nil)
+}
+
+// FromStatusErrorWithOptions converts a gRPC status error into a merr error.
+// The code is recovered from errdetails.ErrorInfo when its reason is a
+// registered code or comes from the trusted domain, falling back to a
+// reverse mapping of the gRPC code. The field violations of
+// errdetails.BadRequest are attached as a *merr.ValidationError and
+// errdetails.RetryInfo becomes a retry hint. Statuses with several
+// ErrorInfo details become a *merr.Multi, whose first member carries the
+// violations. The status error stays in the chain, so status.FromError
+// keeps working. Errors that are not statuses, such as io.EOF, are
+// returned unchanged.
+func FromStatusErrorWithOptions(err error, opts *GRPCClientErrorInterceptorOptions) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := merr.AsPublic(err); ok {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return err
+	}
+
+	var infos []*errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			infos = append(infos, d)
+		case *errdetails.BadRequest:
+			badRequest = d
+		case *errdetails.RetryInfo:
+			retryInfo = d
+		}
+	}
+
+	if len(infos) <= 1 {
+		code := merr.FromGRPCCode(st.Code())
+		var meta map[string]string
+		if len(infos) == 1 {
+			code = opts.reasonCode(infos[0], st.Code())
+			meta = infos[0].Metadata
+		}
+
+		pe := withMeta(merr.New(code, st.Message(), withViolations(err, badRequest, code, st.Message())), meta)
+		return withRetryInfo(pe, retryInfo)
+	}
+
+	// Aggregated error: each ErrorInfo keeps its public message in the metadata
+	members := make([]error, 0, len(infos))
+	for i, info := range infos {
+		meta := make(map[string]string, len(info.Metadata))
+		for k, v := range info.Metadata {
+			meta[k] = v
+		}
+		public := meta[MessageMetadataKey]
+		delete(meta, MessageMetadataKey)
+
+		code := opts.reasonCode(info, st.Code())
+		var cause error
+		if i == 0 {
+			// The status and its violations are kept once, on the first member
+			cause = withViolations(err, badRequest, code, public)
+		}
+		members = append(members, withRetryInfo(withMeta(merr.New(code, public, cause), meta), retryInfo))
+	}
+	var mapper *merr.Mapper
+	if opts != nil {
+		mapper = opts.Mapper
+	}
+	return merr.JoinWithPolicy(matchGRPCCode(mapper, st.Code()), members...)
+}
+
+// withViolations joins err with a *merr.ValidationError holding the field
+// violations of badRequest, if it has any, so that both stay in the chain
+func withViolations(err error, badRequest *errdetails.BadRequest, code merr.ErrCode, public string) error {
+	if badRequest == nil || len(badRequest.FieldViolations) == 0 {
+		return err
+	}
+	v := merr.NewValidationError(code, public)
+	for _, fv := range badRequest.FieldViolations {
+		v.Add(fv.Field, fv.Reason, fv.Description)
+	}
+	return errors.Join(v, err)
+}
+
+// withRetryInfo attaches the retry delay of retryInfo to err, if any
+func withRetryInfo(err merr.Error, retryInfo *errdetails.RetryInfo) merr.Error {
+	if retryInfo == nil || retryInfo.RetryDelay == nil {
+		return err
+	}
+	return err.RetryAfter(retryInfo.RetryDelay.AsDuration())
+}
+
+// reasonCode returns the code carried by info if its reason is a registered
+// code or comes from the trusted domain, or the reverse mapping of code otherwise
+func (o *GRPCClientErrorInterceptorOptions) reasonCode(info *errdetails.ErrorInfo, code codes.Code) merr.ErrCode {
+	reason := merr.ErrCode(info.Reason)
+	if _, ok := merr.Lookup(reason); ok {
+		return reason
+	}
+	if o != nil && o.Domain != "" && info.Domain == o.Domain && reason != "" {
+		return reason
+	}
+	return merr.FromGRPCCode(code)
+}
+
+// withMeta attaches every metadata entry to err
+func withMeta(err merr.Error, meta map[string]string) merr.Error {
+	for k, v := range meta {
+		err = err.WithMeta(k, v)
+	}
+	return err
+}
+
+// matchGRPCCode is a merr.CodePolicy selecting the first error that mapper maps to the given gRPC code
+func matchGRPCCode(mapper *merr.Mapper, code codes.Code) merr.CodePolicy {
+	return func(errs []merr.PublicErr) merr.ErrCode {
+		for _, pe := range errs {
+			if mapper.ToGRPCCode(pe.Code()) == code {
+				return pe.Code()
+			}
+		}
+		return errs[0].Code()
+	}
+}
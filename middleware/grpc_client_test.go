@@ -0,0 +1,261 @@
+package merrmid
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mandacode-com/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// serverError runs err through the server interceptor and returns the status error sent to clients
+func serverError(t *testing.T, err error) error {
+	t.Helper()
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{Domain: "test"})
+	_, statusErr := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) { return nil, err },
+	)
+	return statusErr
+}
+
+// invokeWith calls the client interceptor with an invoker returning err
+func invokeWith(err error) error {
+	interceptor := GRPCClientErrorInterceptor()
+	return interceptor(
+		context.Background(),
+		"/test.Service/Method",
+		nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return err
+		},
+	)
+}
+
+func TestGRPCClientErrorInterceptor_RecoversExactCode(t *testing.T) {
+	sent := merr.New(merr.ErrLoopDetected, "Redirect loop", nil).WithMeta("hops", "12")
+
+	err := invokeWith(serverError(t, sent))
+
+	assert.True(t, merr.CheckCode(err, merr.ErrLoopDetected), "exact code should be recovered from ErrorInfo")
+	pe, ok := merr.AsPublic(err)
+	require.True(t, ok)
+	assert.Equal(t, "Redirect loop", pe.Public())
+	assert.Equal(t, map[string]string{"hops": "12"}, merr.Metadata(err))
+	assert.Equal(t, codes.Aborted, status.Code(err), "the status should stay reachable")
+}
+
+func TestGRPCClientErrorInterceptor_FallbackMapping(t *testing.T) {
+	err := invokeWith(status.Error(codes.NotFound, "no such user"))
+
+	assert.True(t, merr.CheckCode(err, merr.ErrNotFound))
+	pe, _ := merr.AsPublic(err)
+	assert.Equal(t, "no such user", pe.Public())
+
+	err = invokeWith(status.Error(codes.DataLoss, "corrupted"))
+	assert.True(t, merr.CheckCode(err, merr.ErrInternalServerError))
+}
+
+func TestGRPCClientErrorInterceptor_ValidationError(t *testing.T) {
+	sent := (&merr.ValidationError{}).Add("email", "required", "email is required").Err()
+
+	err := invokeWith(serverError(t, sent))
+
+	assert.True(t, merr.CheckCode(err, merr.ErrInvalidInput))
+	assert.Equal(t, []merr.Violation{
+		{Field: "email", Rule: "required", Message: "email is required"},
+	}, merr.Violations(err))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the status should stay reachable")
+}
+
+func TestGRPCClientErrorInterceptor_BadRequestDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid order").WithDetails(
+		&errdetails.ErrorInfo{Reason: string(merr.ErrInvalidInput), Metadata: map[string]string{"order": "42"}},
+		&errdetails.BadRequest{},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)},
+	)
+	require.NoError(t, err)
+
+	err = invokeWith(st.Err())
+	require.Error(t, err, "an empty BadRequest should not drop the error")
+	assert.True(t, merr.CheckCode(err, merr.ErrInvalidInput))
+	assert.Empty(t, merr.Violations(err))
+
+	st, err = status.New(codes.InvalidArgument, "invalid order").WithDetails(
+		&errdetails.ErrorInfo{Reason: string(merr.ErrInvalidInput), Metadata: map[string]string{"order": "42"}},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "quantity", Reason: "min", Description: "quantity must be at least 1"},
+		}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)},
+	)
+	require.NoError(t, err)
+
+	err = invokeWith(st.Err())
+	assert.Equal(t, []merr.Violation{
+		{Field: "quantity", Rule: "min", Message: "quantity must be at least 1"},
+	}, merr.Violations(err))
+	assert.Equal(t, map[string]string{"order": "42"}, merr.Metadata(err))
+	delay, ok := merr.RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "the status should stay reachable")
+}
+
+func TestGRPCClientErrorInterceptor_Domain(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "quota exceeded").WithDetails(
+		&errdetails.ErrorInfo{Reason: "RATE_LIMIT_EXCEEDED", Domain: "googleapis.com"},
+	)
+	require.NoError(t, err)
+
+	err = invokeWith(st.Err())
+	assert.True(t, merr.CheckCode(err, merr.ErrTooManyRequests), "reasons from other domains should not be trusted")
+	assert.True(t, merr.IsRetryable(err))
+
+	st, err = status.New(codes.FailedPrecondition, "payment declined").WithDetails(
+		&errdetails.ErrorInfo{Reason: "payment_declined", Domain: "test"},
+	)
+	require.NoError(t, err)
+
+	interceptor := GRPCClientErrorInterceptorWithOptions(&GRPCClientErrorInterceptorOptions{Domain: "test"})
+	err = interceptor(
+		context.Background(),
+		"/test.Service/Method",
+		nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return st.Err()
+		},
+	)
+	assert.True(t, merr.CheckCode(err, "payment_declined"), "reasons from the trusted domain should be kept")
+	assert.True(t, merr.CheckCode(FromStatusError(st.Err()), merr.ErrPreconditionFailed), "no domain is trusted by default")
+}
+
+func TestGRPCClientErrorInterceptor_MultiError(t *testing.T) {
+	sent := merr.JoinWithPolicy(merr.HighestStatusCode,
+		merr.New(merr.ErrNotFound, "Item 1 not found", nil),
+		merr.New(merr.ErrConflict, "Item 2 already exists", nil).WithMeta("message", "kept"),
+	)
+
+	err := invokeWith(serverError(t, sent))
+
+	var multi *merr.Multi
+	require.True(t, errors.As(err, &multi))
+	assert.Equal(t, merr.ErrConflict, multi.Code())
+	require.Len(t, multi.PublicErrors(), 2)
+	assert.Equal(t, "Item 1 not found", multi.PublicErrors()[0].Public())
+	assert.Equal(t, merr.ErrNotFound, multi.PublicErrors()[0].Code())
+	assert.Nil(t, merr.Metadata(multi.PublicErrors()[0]))
+	assert.Equal(t, "Item 2 already exists", multi.PublicErrors()[1].Public())
+	assert.Equal(t, map[string]string{"message": "kept"}, merr.Metadata(multi.PublicErrors()[1]), "user metadata should not collide with the member message")
+}
+
+func TestGRPCClientErrorInterceptor_MultiErrorDetails(t *testing.T) {
+	sent := merr.Join(
+		merr.New(merr.ErrNotFound, "Item 1 not found", nil).RetryAfter(time.Second),
+		(&merr.ValidationError{}).Add("quantity", "min", "quantity must be at least 1").Err(),
+	)
+	statusErr := serverError(t, sent)
+
+	err := invokeWith(statusErr)
+
+	var multi *merr.Multi
+	require.True(t, errors.As(err, &multi))
+	require.Len(t, multi.PublicErrors(), 2)
+	assert.Equal(t, []merr.Violation{
+		{Field: "quantity", Rule: "min", Message: "quantity must be at least 1"},
+	}, merr.Violations(err))
+	delay, ok := merr.RetryAfter(err)
+	assert.True(t, ok, "the retry hint should be kept")
+	assert.Equal(t, time.Second, delay)
+	assert.Equal(t, status.Code(statusErr), status.Code(err), "the status should stay reachable")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCClientErrorInterceptor_MultiErrorMapper(t *testing.T) {
+	st, err := status.New(codes.FailedPrecondition, "several errors").WithDetails(
+		&errdetails.ErrorInfo{Reason: string(merr.ErrConflict), Metadata: map[string]string{MessageMetadataKey: "Item 1 already exists"}},
+		&errdetails.ErrorInfo{Reason: string(merr.ErrNotFound), Metadata: map[string]string{MessageMetadataKey: "Item 2 not found"}},
+	)
+	require.NoError(t, err)
+
+	opts := &GRPCClientErrorInterceptorOptions{
+		Mapper: merr.NewMapper().WithGRPCCode(merr.ErrNotFound, codes.FailedPrecondition),
+	}
+	var multi *merr.Multi
+	require.True(t, errors.As(FromStatusErrorWithOptions(st.Err(), opts), &multi))
+	assert.Equal(t, merr.ErrNotFound, multi.Code(), "the code should be matched with the mapper")
+}
+
+func TestGRPCClientErrorInterceptor_PassThrough(t *testing.T) {
+	assert.NoError(t, invokeWith(nil))
+	assert.Equal(t, io.EOF, FromStatusError(io.EOF))
+
+	plain := errors.New("dial failed")
+	assert.Equal(t, plain, invokeWith(plain))
+}
+
+// fakeClientStream returns fixed errors
+type fakeClientStream struct {
+	grpc.ClientStream
+	recvErr error
+}
+
+func (s *fakeClientStream) RecvMsg(m any) error {
+	return s.recvErr
+}
+
+func TestGRPCStreamClientErrorInterceptor(t *testing.T) {
+	interceptor := GRPCStreamClientErrorInterceptor()
+
+	streamer := func(recvErr error) grpc.Streamer {
+		return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &fakeClientStream{recvErr: recvErr}, nil
+		}
+	}
+
+	cs, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+		streamer(serverError(t, merr.New(merr.ErrTooManyRequests, "Slow down", nil))))
+	require.NoError(t, err)
+	assert.True(t, merr.CheckCode(cs.RecvMsg(nil), merr.ErrTooManyRequests))
+
+	cs, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Stream", streamer(io.EOF))
+	require.NoError(t, err)
+	assert.Equal(t, io.EOF, cs.RecvMsg(nil), "io.EOF must pass through unchanged")
+
+	_, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, "/test.Service/Stream",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.Error(codes.Unavailable, "connection refused")
+		})
+	assert.True(t, merr.CheckCode(err, merr.ErrServiceUnavailable))
+}
+
+func TestGRPCClientErrorInterceptor_RetryInfo(t *testing.T) {
+	sent := merr.New(merr.ErrServiceUnavailable, "Try again later", nil).RetryAfter(5 * time.Second)
+
+	statusErr := serverError(t, sent)
+	st, _ := status.FromError(statusErr)
+	var retryInfo *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			retryInfo = ri
+		}
+	}
+	require.NotNil(t, retryInfo, "the server should attach RetryInfo")
+	assert.Equal(t, 5*time.Second, retryInfo.RetryDelay.AsDuration())
+
+	err := invokeWith(statusErr)
+	delay, ok := merr.RetryAfter(err)
+	assert.True(t, ok, "the client should recover the retry hint")
+	assert.Equal(t, 5*time.Second, delay)
+	assert.True(t, merr.IsRetryable(err))
+}
@@ -1,15 +1,24 @@
 package merrmid
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/mandacode-com/merr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -109,4 +118,402 @@ func TestNewPublicError(t *testing.T) {
 	assert.Equal(t, "Public message", publicErr.Public())
 	assert.Equal(t, merr.ErrBadRequest, publicErr.Code())
 	assert.Equal(t, "base error", err.Error())
-}
\ No newline at end of file
+}
+func TestGRPCErrorInterceptor_WrappedPublicError(t *testing.T) {
+	interceptor := GRPCErrorInterceptor()
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, fmt.Errorf("lookup: %w", merr.New(merr.ErrNotFound, "Resource not found", nil))
+	}
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		handler,
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "Resource not found", st.Message())
+}
+
+func TestGRPCErrorInterceptor_JoinedPublicError(t *testing.T) {
+	interceptor := GRPCErrorInterceptor()
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, merr.JoinWithPolicy(merr.MostSevereCode,
+			merr.New(merr.ErrNotFound, "Item 1 not found", nil),
+			merr.New(merr.ErrServiceUnavailable, "Inventory unavailable", nil),
+		)
+	}
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		handler,
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "Item 1 not found; Inventory unavailable", st.Message())
+
+	details := st.Details()
+	require.Len(t, details, 3)
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(merr.ErrNotFound), info.Reason)
+	assert.Equal(t, "Item 1 not found", info.Metadata[MessageMetadataKey])
+	info, ok = details[1].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(merr.ErrServiceUnavailable), info.Reason)
+}
+
+func TestGRPCErrorInterceptor_ValidationError(t *testing.T) {
+	interceptor := GRPCErrorInterceptor()
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		v := &merr.ValidationError{}
+		v.Add("email", "required", "email is required")
+		return nil, v.Err()
+	}
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		handler,
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "Invalid input", st.Message())
+
+	details := st.Details()
+	require.Len(t, details, 3)
+	badRequest, ok := details[1].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.FieldViolations, 1)
+	assert.Equal(t, "email", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "required", badRequest.FieldViolations[0].Reason)
+	assert.Equal(t, "email is required", badRequest.FieldViolations[0].Description)
+}
+
+func TestGRPCErrorInterceptor_ErrorInfo(t *testing.T) {
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		Domain: "orders.example.com",
+	})
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, merr.New(merr.ErrConflict, "Order already paid", nil).
+			WithMeta("orderId", "o-1").
+			With("internal", "not sent")
+	}
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		handler,
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.Aborted, st.Code())
+
+	details := st.Details()
+	require.Len(t, details, 2)
+	info, ok := details[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, string(merr.ErrConflict), info.Reason)
+	assert.Equal(t, "orders.example.com", info.Domain)
+	assert.Equal(t, map[string]string{"orderId": "o-1"}, info.Metadata)
+}
+
+func TestGRPCErrorInterceptor_Mapper(t *testing.T) {
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		Mapper: merr.NewMapper().WithGRPCCode(merr.ErrUnprocessableEntity, codes.InvalidArgument),
+	})
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, merr.New(merr.ErrUnprocessableEntity, "Cannot process order", nil)
+	}
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		handler,
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestGRPCErrorInterceptor_Localized(t *testing.T) {
+	catalog := merr.NewMessageCatalog()
+	catalog.Add("ko", map[string]string{"order.not_found": "주문 {id}을(를) 찾을 수 없습니다"})
+
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		Translator: catalog,
+	})
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		return nil, merr.New(merr.ErrNotFound, "Order not found", nil).
+			WithMessageKey("order.not_found", map[string]any{"id": "o-1"})
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "ko-KR"))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, "주문 o-1을(를) 찾을 수 없습니다", st.Message())
+
+	var localized *errdetails.LocalizedMessage
+	for _, d := range st.Details() {
+		if m, ok := d.(*errdetails.LocalizedMessage); ok {
+			localized = m
+		}
+	}
+	require.NotNil(t, localized)
+	assert.Equal(t, "ko-KR", localized.Locale)
+	assert.Equal(t, "주문 o-1을(를) 찾을 수 없습니다", localized.Message)
+
+	// Without a matching locale the public message is kept and no
+	// LocalizedMessage is attached.
+	_, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+	st, _ = status.FromError(err)
+	assert.Equal(t, "Order not found", st.Message())
+	for _, d := range st.Details() {
+		_, localized := d.(*errdetails.LocalizedMessage)
+		assert.False(t, localized)
+	}
+}
+
+func TestGRPCErrorInterceptor_LocaleMetadataKey(t *testing.T) {
+	catalog := merr.NewMessageCatalog()
+	catalog.Add("ko", map[string]string{"order.not_found": "주문을 찾을 수 없습니다"})
+
+	interceptor := GRPCStreamErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		Translator:        catalog,
+		LocaleMetadataKey: "x-locale",
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-locale", "ko"))
+	err := interceptor(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(srv any, stream grpc.ServerStream) error {
+			return merr.New(merr.ErrNotFound, "Order not found", nil).WithMessageKey("order.not_found", nil)
+		})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, "주문을 찾을 수 없습니다", st.Message())
+}
+
+// contextStream is a grpc.ServerStream carrying only a context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
+
+func TestGRPCErrorInterceptor_ClassifyErrors(t *testing.T) {
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{ClassifyErrors: true})
+
+	_, err := interceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) {
+			return nil, fmt.Errorf("stream closed: %w", context.Canceled)
+		},
+	)
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Canceled, st.Code())
+	assert.Equal(t, "Canceled", st.Message())
+
+	// Without the option the error stays internal
+	_, err = GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{})(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) { return nil, context.Canceled },
+	)
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestGRPCErrorInterceptor_RecoverPanics(t *testing.T) {
+	var internal error
+	opts := &GRPCErrorInterceptorOptions{
+		RecoverPanics: true,
+		OnInternalError: func(ctx context.Context, err error) error {
+			internal = err
+			return nil
+		},
+	}
+
+	_, err := GRPCErrorInterceptorWithOptions(opts)(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(ctx context.Context, req any) (any, error) { panic("nil map") },
+	)
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "Internal server error", status.Convert(err).Message())
+	require.Error(t, internal, "panics should be routed through OnInternalError")
+	assert.True(t, merr.CheckCode(internal, merr.ErrInternalServerError))
+	assert.Equal(t, "panic: nil map", internal.Error())
+
+	internal = nil
+	cause := errors.New("boom")
+	err = GRPCStreamErrorInterceptorWithOptions(opts)(
+		nil,
+		&contextStream{ctx: context.Background()},
+		&grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(srv any, stream grpc.ServerStream) error { panic(cause) },
+	)
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.ErrorIs(t, internal, cause, "error panic values should stay in the chain")
+}
+
+func TestGRPCErrorInterceptor_RecoverPanicsErrAbortHandler(t *testing.T) {
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{RecoverPanics: true})
+
+	var err error
+	assert.NotPanics(t, func() {
+		_, err = interceptor(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+			func(ctx context.Context, req any) (any, error) { panic(http.ErrAbortHandler) },
+		)
+	}, "gRPC servers do not recover panics, so http.ErrAbortHandler must not be re-panicked")
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestGRPCErrorInterceptor_Logger(t *testing.T) {
+	var logs bytes.Buffer
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		LogErrors: true,
+		Logger:    slog.New(slog.NewJSONHandler(&logs, nil)),
+	})
+
+	for _, err := range []error{
+		merr.New(merr.ErrNotFound, "Order not found", nil).With("order_id", "o-1"),
+		errors.New("db down"),
+	} {
+		interceptor(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+			func(ctx context.Context, req any) (any, error) { return nil, err },
+		)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var public map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &public))
+	assert.Equal(t, "WARN", public["level"])
+	assert.Equal(t, "public error", public["msg"])
+	assert.Equal(t, "/test.Service/Method", public["method"])
+	assert.Equal(t, "NotFound", public["grpc_code"])
+	assert.Equal(t, "not_found", public["code"])
+	assert.Equal(t, map[string]any{"order_id": "o-1"}, public["fields"])
+	assert.NotEmpty(t, public["stack"])
+
+	var internal map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &internal))
+	assert.Equal(t, "ERROR", internal["level"])
+	assert.Equal(t, "Internal", internal["grpc_code"])
+	assert.Equal(t, "db down", internal["error"])
+}
+
+func TestGRPCErrorInterceptor_LoggerChainFields(t *testing.T) {
+	var logs bytes.Buffer
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		LogErrors: true,
+		Logger:    slog.New(slog.NewJSONHandler(&logs, nil)),
+	})
+
+	for _, err := range []error{
+		merr.Join(
+			merr.New(merr.ErrNotFound, "a", nil).With("order_id", "o-1"),
+			merr.New(merr.ErrNotFound, "b", nil).With("user_id", "u-1"),
+		),
+		fmt.Errorf("outer: %w", merr.WithField(io.EOF, "k", "v")),
+	} {
+		interceptor(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+			func(ctx context.Context, req any) (any, error) { return nil, err },
+		)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var joined, wrapped map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &joined))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &wrapped))
+	assert.Equal(t, map[string]any{"order_id": "o-1", "user_id": "u-1"}, joined["fields"], "fields of aggregated errors should be logged")
+	assert.Equal(t, map[string]any{"k": "v"}, wrapped["fields"], "fields behind fmt.Errorf should be logged")
+}
+
+func TestGRPCErrorInterceptor_RequestID(t *testing.T) {
+	var hookID string
+	interceptor := GRPCErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		OnInternalError: func(ctx context.Context, err error) error {
+			hookID = RequestID(ctx)
+			return nil
+		},
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-7"))
+	for _, handlerErr := range []error{
+		merr.New(merr.ErrNotFound, "Order not found", nil),
+		errors.New("db down"),
+	} {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+			func(ctx context.Context, req any) (any, error) { return nil, handlerErr })
+
+		var info *errdetails.RequestInfo
+		for _, d := range status.Convert(err).Details() {
+			if ri, ok := d.(*errdetails.RequestInfo); ok {
+				info = ri
+			}
+		}
+		require.NotNil(t, info, "%v should carry RequestInfo", handlerErr)
+		assert.Equal(t, "req-7", info.RequestId)
+	}
+	assert.Equal(t, "req-7", hookID)
+
+	stream := GRPCStreamErrorInterceptorWithOptions(&GRPCErrorInterceptorOptions{
+		RequestIDMetadataKey: "x-correlation-id",
+		GenerateRequestID:    func() string { return "generated" },
+	})
+	err := stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+		func(srv any, stream grpc.ServerStream) error { return errors.New("db down") })
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	assert.Equal(t, "generated", details[0].(*errdetails.RequestInfo).RequestId)
+}
@@ -0,0 +1,213 @@
+package merrmid
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/mandacode-com/merr"
+)
+
+// ErrorHandlerFunc adapts a handler returning an error to http.Handler.
+// Behind HTTPErrorHandler the error is passed to the middleware; otherwise
+// it is rendered with the default options of HTTPErrorHandler.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f and handles the error it returns.
+func (f ErrorHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := f(w, r)
+	if err == nil {
+		return
+	}
+	if rec, ok := r.Context().Value(errorRecorderKey{}).(*errorRecorder); ok {
+		rec.add(err)
+		return
+	}
+	handleHTTPErrors(w, r, defaultHTTPErrorHandlerOptions, []error{err})
+}
+
+// HTTPErrorHandler is a net/http middleware that converts the errors returned
+// by ErrorHandlerFunc handlers to JSON responses, exactly like GinErrorHandler.
+func HTTPErrorHandler(next http.Handler) http.Handler {
+	return HTTPErrorHandlerWithOptions(nil)(next)
+}
+
+// HTTPErrorHandlerOptions provides configuration options for the net/http error handler
+type HTTPErrorHandlerOptions struct {
+	// LogErrors determines whether to log public and internal errors (default: true)
+	LogErrors bool
+	// Logger receives the error logs (default: slog.Default())
+	Logger *slog.Logger
+	// LogLevel returns the level of errors rendered with the given status (default: DefaultHTTPLogLevel)
+	LogLevel func(status int) slog.Level
+	// CustomErrorResponse allows customizing the error response format
+	CustomErrorResponse func(w http.ResponseWriter, r *http.Request, publicErr merr.PublicErr)
+	// OnInternalError is called when a non-public error occurs
+	OnInternalError func(w http.ResponseWriter, r *http.Request, err error)
+	// CodePolicy derives the response code when several public errors occur (default: merr.FirstCode).
+	// Use the policies of Mapper, e.g. Mapper.HighestStatusCode(), to rank codes with its statuses
+	CodePolicy merr.CodePolicy
+	// ProblemDetails renders errors as RFC 9457 application/problem+json instead of ErrorResponse
+	ProblemDetails bool
+	// ProblemTypes maps error codes to problem type URIs and titles
+	ProblemTypes map[merr.ErrCode]ProblemType
+	// ProblemTypeBaseURI builds the type URI of unregistered codes as base + code (default: "about:blank")
+	ProblemTypeBaseURI string
+	// ProblemInstance returns the instance member of problem details (default: the request path)
+	ProblemInstance func(r *http.Request) string
+	// Mapper maps error codes to HTTP statuses (default: the merr registry)
+	Mapper *merr.Mapper
+	// Translator localizes public messages into the locales negotiated from Accept-Language
+	Translator merr.Translator
+	// DefaultLocale is tried when no Accept-Language locale has a translation
+	DefaultLocale string
+	// ClassifyErrors converts non-public errors recognized by merr.Classify,
+	// such as context.DeadlineExceeded or sql.ErrNoRows, into public errors
+	ClassifyErrors bool
+	// RecoverPanics converts panics into internal errors, handled like
+	// non-public errors, except http.ErrAbortHandler, which is re-panicked (default: false)
+	RecoverPanics bool
+	// RequestIDHeader is the header carrying the request ID reported with errors,
+	// echoed in the response (default: DefaultRequestIDHeader)
+	RequestIDHeader string
+	// GenerateRequestID creates the ID of requests without one (default: random hex IDs)
+	GenerateRequestID func() string
+}
+
+// defaultHTTPErrorHandlerOptions are the options of HTTPErrorHandler
+var defaultHTTPErrorHandlerOptions = &HTTPErrorHandlerOptions{
+	LogErrors: true,
+}
+
+// HTTPErrorHandlerWithOptions creates a net/http error handling middleware with custom options
+func HTTPErrorHandlerWithOptions(opts *HTTPErrorHandlerOptions) func(http.Handler) http.Handler {
+	if opts == nil {
+		opts = defaultHTTPErrorHandlerOptions
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &errorRecorder{}
+			// The mux of next sets the matched pattern on this request
+			r = r.WithContext(context.WithValue(r.Context(), errorRecorderKey{}, rec))
+			var panicErr error
+			func() {
+				if opts.RecoverPanics {
+					defer recoverHTTPPanic(&panicErr)
+				}
+				next.ServeHTTP(w, r)
+			}()
+
+			if panicErr != nil {
+				writeHTTPInternalError(w, r, opts, panicErr)
+				return
+			}
+			if errs := rec.errors(); len(errs) > 0 {
+				handleHTTPErrors(w, r, opts, errs)
+			}
+		})
+	}
+}
+
+// handleHTTPErrors renders the errors of a request
+func handleHTTPErrors(w http.ResponseWriter, r *http.Request, opts *HTTPErrorHandlerOptions, errs []error) {
+	pe, internalErr := collectErrors(errs, opts.ClassifyErrors, opts.CodePolicy)
+
+	if pe != nil {
+		opts.httpLog(w, r).log(r, "public error", opts.Mapper.ToHTTPStatus(pe.Code()), pe.Code(), pe)
+		if opts.CustomErrorResponse != nil {
+			opts.CustomErrorResponse(w, r, pe)
+		} else {
+			writeHTTPError(w, r, opts, pe)
+		}
+		return
+	}
+
+	// Handle internal error
+	if internalErr != nil {
+		writeHTTPInternalError(w, r, opts, internalErr)
+	}
+}
+
+// writeHTTPInternalError logs a non-public error and reports it to the client
+// as an internal server error
+func writeHTTPInternalError(w http.ResponseWriter, r *http.Request, opts *HTTPErrorHandlerOptions, err error) {
+	code := internalServerError.Code()
+	opts.httpLog(w, r).log(r, "internal error", opts.Mapper.ToHTTPStatus(code), code, err)
+
+	if opts.OnInternalError != nil {
+		opts.OnInternalError(w, r, err)
+	} else {
+		writeHTTPError(w, r, opts, internalServerError)
+	}
+}
+
+// httpLog returns where and how errors of the request are logged
+func (opts *HTTPErrorHandlerOptions) httpLog(w http.ResponseWriter, r *http.Request) httpLog {
+	return httpLog{
+		enabled: opts.LogErrors,
+		logger:  opts.Logger,
+		level:   opts.LogLevel,
+		route:   r.Pattern,
+		reqID:   opts.requestID(w, r),
+	}
+}
+
+// requestID returns the ID reported with the errors of the request
+func (opts *HTTPErrorHandlerOptions) requestID(w http.ResponseWriter, r *http.Request) string {
+	return httpRequestID(r, w.Header(), opts.RequestIDHeader, opts.GenerateRequestID)
+}
+
+// writeHTTPError renders a public error in the configured format
+func writeHTTPError(w http.ResponseWriter, r *http.Request, opts *HTTPErrorHandlerOptions, publicErr merr.PublicErr) {
+	ro := renderOptions{
+		ProblemDetails:     opts.ProblemDetails,
+		ProblemTypes:       opts.ProblemTypes,
+		ProblemTypeBaseURI: opts.ProblemTypeBaseURI,
+		Mapper:             opts.Mapper,
+		Translator:         opts.Translator,
+		DefaultLocale:      opts.DefaultLocale,
+		RequestID:          opts.requestID(w, r),
+	}
+	instance := func() string {
+		if opts.ProblemInstance != nil {
+			return opts.ProblemInstance(r)
+		}
+		return r.URL.Path
+	}
+	status, body := renderError(w.Header(), r, ro, instance, publicErr)
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// errorRecorderKey is the context key of the errorRecorder of a request
+type errorRecorderKey struct{}
+
+// errorRecorder collects the errors returned by the handlers of a request
+type errorRecorder struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (rec *errorRecorder) add(err error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.errs = append(rec.errs, err)
+}
+
+func (rec *errorRecorder) errors() []error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.errs
+}
@@ -0,0 +1,211 @@
+package merrmid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mandacode-com/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPErrorHandler_PublicError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return merr.New(merr.ErrNotFound, "User not found", errors.New("no rows"))
+	}))
+	h := HTTPErrorHandler(mux)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/users/1", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "User not found", response.Error)
+	assert.Equal(t, merr.ErrNotFound, response.Code)
+}
+
+func TestHTTPErrorHandler_InternalError(t *testing.T) {
+	var internal error
+	h := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{
+		OnInternalError: func(w http.ResponseWriter, r *http.Request, err error) {
+			internal = err
+			w.WriteHeader(http.StatusTeapot)
+		},
+	})(ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("database connection failed")
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.EqualError(t, internal, "database connection failed")
+}
+
+func TestHTTPErrorHandler_NoErrors(t *testing.T) {
+	h := HTTPErrorHandler(ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestErrorHandlerFunc_WithoutMiddleware(t *testing.T) {
+	h := ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("internal error")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Internal server error", response.Error)
+	assert.Equal(t, merr.ErrInternalServerError, response.Code)
+}
+
+func TestHTTPErrorHandler_CustomErrorResponse(t *testing.T) {
+	h := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{
+		CustomErrorResponse: func(w http.ResponseWriter, r *http.Request, publicErr merr.PublicErr) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(publicErr.Public()))
+		},
+	})(ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return merr.New(merr.ErrNotFound, "User not found", nil)
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "User not found", w.Body.String())
+}
+
+// TestHTTPErrorHandler_MatchesGin checks that both handlers render identical responses
+func TestHTTPErrorHandler_MatchesGin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	errs := map[string]error{
+		"public": merr.New(merr.ErrNotFound, "User not found", nil).WithMeta("userId", "u-1"),
+		"joined": merr.Join(
+			merr.New(merr.ErrNotFound, "User not found", nil),
+			merr.New(merr.ErrConflict, "Version mismatch", nil),
+		),
+		"validation": (&merr.ValidationError{}).Add("email", "required", "email is required").Err(),
+		"templated":  merr.Newf(merr.ErrNotFound, "Order {id} was not found", map[string]any{"id": "<o-1>"}, nil),
+		"internal":   errors.New("boom"),
+	}
+
+	for _, problem := range []bool{false, true} {
+		for name, err := range errs {
+			r := gin.New()
+			r.Use(GinErrorHandlerWithOptions(&GinErrorHandlerOptions{ProblemDetails: problem}))
+			r.GET("/test", func(c *gin.Context) { c.Error(err) })
+
+			h := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{ProblemDetails: problem})(
+				ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error { return err }),
+			)
+
+			ginW := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			r.ServeHTTP(ginW, req)
+
+			httpW := httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", "/test", nil)
+			req.Header.Set("X-Request-ID", "req-1")
+			h.ServeHTTP(httpW, req)
+
+			assert.Equal(t, ginW.Code, httpW.Code, name)
+			assert.Equal(t, ginW.Header().Get("Content-Type"), httpW.Header().Get("Content-Type"), name)
+			assert.JSONEq(t, ginW.Body.String(), httpW.Body.String(), name)
+		}
+	}
+}
+
+func TestHTTPErrorHandler_RecoverPanics(t *testing.T) {
+	h := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{RecoverPanics: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(errors.New("boom"))
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, merr.ErrInternalServerError, response.Code)
+
+	abort := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{RecoverPanics: true})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}),
+	)
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { abort.ServeHTTP(httptest.NewRecorder(), req) })
+
+	unrecovered := HTTPErrorHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	assert.Panics(t, func() { unrecovered.ServeHTTP(httptest.NewRecorder(), req) }, "recovery should be opt-in")
+}
+
+func TestHTTPErrorHandler_Logger(t *testing.T) {
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.Handle("GET /orders/{id}", ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return merr.New(merr.ErrConflict, "Version mismatch", nil)
+	}))
+	h := HTTPErrorHandlerWithOptions(&HTTPErrorHandlerOptions{
+		LogErrors: true,
+		Logger:    slog.New(slog.NewJSONHandler(&logs, nil)),
+	})(mux)
+
+	req, _ := http.NewRequest("GET", "/orders/o-1", nil)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "GET /orders/{id}", record["route"])
+	assert.Equal(t, "conflict", record["code"])
+	assert.Equal(t, float64(http.StatusConflict), record["status"])
+}
+
+func TestHTTPErrorHandler_RequestID(t *testing.T) {
+	h := HTTPErrorHandler(ErrorHandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return merr.New(merr.ErrNotFound, "User not found", nil)
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	h.ServeHTTP(w, req)
+
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.RequestID, 32, "a hex ID should be generated")
+	assert.Equal(t, response.RequestID, w.Header().Get("X-Request-ID"))
+}
@@ -0,0 +1,71 @@
+package merrmid
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mandacode-com/merr"
+	"golang.org/x/text/language"
+)
+
+// localizer translates public messages into the locales preferred by a request.
+// A nil localizer leaves messages untranslated.
+type localizer struct {
+	translator merr.Translator
+	locales    []string
+}
+
+// newLocalizer creates a localizer trying the preferred locales in order,
+// then the default locale. It returns nil without a translator.
+func newLocalizer(t merr.Translator, preferred []string, defaultLocale string) *localizer {
+	if t == nil {
+		return nil
+	}
+	locales := preferred
+	if defaultLocale != "" {
+		locales = append(locales[:len(locales):len(locales)], defaultLocale)
+	}
+	return &localizer{translator: t, locales: locales}
+}
+
+// message returns the public message of publicErr in the first locale
+// with a translation, and that locale. Aggregated errors are translated
+// member by member. The locale is empty if nothing was translated.
+func (l *localizer) message(publicErr merr.PublicErr) (string, string) {
+	if l == nil {
+		return publicErr.Public(), ""
+	}
+	var multi *merr.Multi
+	if errors.As(publicErr, &multi) {
+		var locale string
+		msgs := make([]string, 0, len(multi.PublicErrors()))
+		for _, pe := range multi.PublicErrors() {
+			msg, loc := l.message(pe)
+			if locale == "" {
+				locale = loc
+			}
+			msgs = append(msgs, msg)
+		}
+		return strings.Join(msgs, "; "), locale
+	}
+	for _, locale := range l.locales {
+		if msg, ok := merr.Localize(l.translator, locale, publicErr); ok {
+			return msg, locale
+		}
+	}
+	return publicErr.Public(), ""
+}
+
+// parseAcceptLanguage returns the locales of an Accept-Language value,
+// most preferred first. Invalid values yield no locales.
+func parseAcceptLanguage(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+	locales := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		locales = append(locales, tag.String())
+	}
+	return locales
+}
@@ -0,0 +1,112 @@
+package merrmid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mandacode-com/merr"
+	"google.golang.org/grpc/codes"
+)
+
+// DefaultHTTPLogLevel logs server errors (5xx) at Error and other statuses at Warn.
+func DefaultHTTPLogLevel(status int) slog.Level {
+	if status >= 500 {
+		return slog.LevelError
+	}
+	return slog.LevelWarn
+}
+
+// DefaultGRPCLogLevel logs the gRPC codes caused by the request at Warn,
+// like 4xx statuses, and other codes at Error.
+func DefaultGRPCLogLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unauthenticated:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+// httpLog describes where and how the errors of an HTTP request are logged
+type httpLog struct {
+	enabled bool
+	logger  *slog.Logger
+	level   func(status int) slog.Level
+	route   string
+	reqID   string
+}
+
+// log records err, handled for r with the given status and code
+func (l httpLog) log(r *http.Request, msg string, status int, code merr.ErrCode, err error) {
+	if !l.enabled {
+		return
+	}
+	level := DefaultHTTPLogLevel
+	if l.level != nil {
+		level = l.level
+	}
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+	}
+	if l.route != "" {
+		attrs = append(attrs, slog.String("route", l.route))
+	}
+	attrs = append(attrs, slog.Int("status", status), slog.String("request_id", l.reqID))
+	logError(r.Context(), l.logger, level(status), msg, code, err, attrs...)
+}
+
+// logError logs err with its merr code, fields and stack after attrs.
+// Errors implementing slog.LogValuer, such as merr errors, are logged as a
+// group. The fields of the whole chain and its innermost stack are logged
+// separately, as aggregated and wrapped errors may not carry them.
+// A nil logger uses slog.Default.
+func logError(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, code merr.ErrCode, err error, attrs ...slog.Attr) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs = append(attrs,
+		slog.String("code", string(code)),
+		slog.Any("error", err),
+	)
+	if fields := merr.Fields(err); len(fields) > 0 {
+		group := make([]any, 0, len(fields))
+		for _, f := range fields {
+			group = append(group, slog.Any(f.Key, f.Value))
+		}
+		attrs = append(attrs, slog.Group("fields", group...))
+	}
+	if stack := stackTrace(err); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// stackTrace formats the innermost stack trace in err's chain,
+// which is the closest to where the error occurred
+func stackTrace(err error) string {
+	var frames []merr.Frame
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if st, ok := e.(merr.StackTracer); ok {
+			if f := st.StackTrace(); len(f) > 0 {
+				frames = f
+			}
+		}
+	}
+	var b strings.Builder
+	for i, f := range frames {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+	return b.String()
+}
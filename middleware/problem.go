@@ -0,0 +1,121 @@
+package merrmid
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mandacode-com/merr"
+)
+
+// ProblemContentType is the media type of RFC 9457 problem details
+const ProblemContentType = "application/problem+json"
+
+// ProblemType describes the problem type registered for an error code
+type ProblemType struct {
+	// Type is a URI reference identifying the problem type
+	Type string
+	// Title is a short, human-readable summary of the problem type
+	Title string
+}
+
+// ProblemDetails represents an RFC 9457 problem details object
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions holds extension members, serialized alongside the standard members
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON flattens the extension members into the problem object.
+// Standard members take precedence over extensions with the same name.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON collects unknown members into Extensions.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	type standard ProblemDetails
+	if err := json.Unmarshal(data, (*standard)(p)); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "title", "status", "detail", "instance"} {
+		delete(members, k)
+	}
+	if len(members) > 0 {
+		p.Extensions = members
+	} else {
+		p.Extensions = nil
+	}
+	return nil
+}
+
+// newProblemDetails builds the problem details for a public error.
+// The merr code, aggregated errors, violations, metadata, message template
+// and request ID are added as extensions.
+func newProblemDetails(ro renderOptions, instance string, status int, publicErr merr.PublicErr, loc *localizer) ProblemDetails {
+	resp := newErrorResponse(publicErr, loc)
+
+	problem := ProblemDetails{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     resp.Error,
+		Extensions: map[string]any{"code": resp.Code},
+	}
+	if problem.Title == "" {
+		// Non-standard statuses such as 499 have no phrase
+		problem.Title = publicErr.Code().Title()
+	}
+	if pt, ok := ro.ProblemTypes[publicErr.Code()]; ok {
+		if pt.Type != "" {
+			problem.Type = pt.Type
+		}
+		if pt.Title != "" {
+			problem.Title = pt.Title
+		}
+	} else if ro.ProblemTypeBaseURI != "" {
+		problem.Type = ro.ProblemTypeBaseURI + string(publicErr.Code())
+	}
+
+	problem.Instance = instance
+
+	if len(resp.Errors) > 0 {
+		problem.Extensions["errors"] = resp.Errors
+	}
+	if len(resp.Violations) > 0 {
+		problem.Extensions["violations"] = resp.Violations
+	}
+	if len(resp.Metadata) > 0 {
+		problem.Extensions["metadata"] = resp.Metadata
+	}
+	if ro.RequestID != "" {
+		problem.Extensions["request_id"] = ro.RequestID
+	}
+	if resp.Template != "" {
+		problem.Extensions["template"] = resp.Template
+		if len(resp.Params) > 0 {
+			problem.Extensions["params"] = resp.Params
+		}
+	}
+	return problem
+}
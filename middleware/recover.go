@@ -0,0 +1,42 @@
+package merrmid
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/mandacode-com/merr"
+)
+
+// recoverPanic is deferred to convert a panic into an internal error stored
+// in *errp. The error is an merr.ErrInternalServerError capturing the stack
+// of the panic, caused by the panic value.
+func recoverPanic(errp *error) {
+	if v := recover(); v != nil {
+		*errp = panicError(v)
+	}
+}
+
+// recoverHTTPPanic is like recoverPanic, but re-panics http.ErrAbortHandler
+// so that net/http can abort the response. gRPC servers do not recover
+// handler panics, so it is only used by the HTTP middlewares.
+func recoverHTTPPanic(errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if v == http.ErrAbortHandler {
+		panic(v)
+	}
+	*errp = panicError(v)
+}
+
+// panicError wraps a panic value, keeping errors in the chain
+func panicError(v any) merr.Error {
+	var cause error
+	if err, ok := v.(error); ok {
+		cause = fmt.Errorf("panic: %w", err)
+	} else {
+		cause = fmt.Errorf("panic: %v", v)
+	}
+	return merr.New(merr.ErrInternalServerError, "Internal server error", cause)
+}
@@ -0,0 +1,116 @@
+package merrmid
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mandacode-com/merr"
+)
+
+// renderOptions holds the options shared by the Gin and net/http error handlers
+// that affect how a public error is rendered
+type renderOptions struct {
+	ProblemDetails     bool
+	ProblemTypes       map[merr.ErrCode]ProblemType
+	ProblemTypeBaseURI string
+	Mapper             *merr.Mapper
+	Translator         merr.Translator
+	DefaultLocale      string
+	RequestID          string
+}
+
+// internalServerError is the public error reported for non-public errors
+var internalServerError = merr.NewWithoutStack(merr.ErrInternalServerError, "Internal server error", nil)
+
+// collectErrors converts the errors of a request and aggregates the public
+// ones with policy. It returns nil if none is public, along with the first
+// non-public error.
+func collectErrors(errs []error, classify bool, policy merr.CodePolicy) (merr.PublicErr, error) {
+	var publicErrs []error
+	var internalErr error
+
+	for _, err := range errs {
+		err = FromValidationErrors(err)
+		if classify {
+			err = classifyError(err)
+		}
+		if _, ok := merr.AsPublic(err); ok {
+			publicErrs = append(publicErrs, err)
+		} else if internalErr == nil {
+			internalErr = err
+		}
+	}
+
+	if len(publicErrs) == 0 {
+		return nil, internalErr
+	}
+	publicErr := publicErrs[0]
+	if len(publicErrs) > 1 {
+		publicErr = merr.JoinWithPolicy(policy, publicErrs...)
+	}
+	pe, _ := merr.AsPublic(publicErr)
+	return pe, internalErr
+}
+
+// renderError sets the response headers of a public error and returns its
+// status and body in the configured format. instance is only called for
+// problem details.
+func renderError(h http.Header, r *http.Request, ro renderOptions, instance func() string, publicErr merr.PublicErr) (int, any) {
+	status := ro.Mapper.ToHTTPStatus(publicErr.Code())
+	loc := newLocalizer(ro.Translator, parseAcceptLanguage(r.Header.Get("Accept-Language")), ro.DefaultLocale)
+	if _, locale := loc.message(publicErr); locale != "" {
+		h.Set("Content-Language", locale)
+	}
+	if delay, ok := merr.RetryAfter(publicErr); ok {
+		h.Set("Retry-After", retryAfterSeconds(delay))
+	}
+	if ro.ProblemDetails {
+		h.Set("Content-Type", ProblemContentType)
+		return status, newProblemDetails(ro, instance(), status, publicErr, loc)
+	}
+	resp := newErrorResponse(publicErr, loc)
+	resp.RequestID = ro.RequestID
+	return status, resp
+}
+
+// newErrorResponse builds the response body for a public error,
+// listing every member when it aggregates several errors and
+// the violations of any validation error.
+func newErrorResponse(publicErr merr.PublicErr, loc *localizer) ErrorResponse {
+	msg, _ := loc.message(publicErr)
+	resp := ErrorResponse{
+		Error:      msg,
+		Code:       publicErr.Code(),
+		Violations: merr.Violations(publicErr),
+		Metadata:   merr.Metadata(publicErr),
+	}
+	resp.Template, resp.Params = messageTemplate(publicErr)
+	var multi *merr.Multi
+	if errors.As(publicErr, &multi) {
+		for _, pe := range multi.PublicErrors() {
+			msg, _ := loc.message(pe)
+			detail := ErrorDetail{
+				Error: msg,
+				Code:  pe.Code(),
+			}
+			detail.Template, detail.Params = messageTemplate(pe)
+			resp.Errors = append(resp.Errors, detail)
+		}
+	}
+	return resp
+}
+
+// retryAfterSeconds formats a retry hint as Retry-After delay-seconds, rounding up
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
+
+// messageTemplate returns the template and parameters of a templated public error
+func messageTemplate(publicErr merr.PublicErr) (string, map[string]any) {
+	if t, ok := publicErr.(merr.Templated); ok && t.MessageTemplate() != "" {
+		return t.MessageTemplate(), t.MessageParams()
+	}
+	return "", nil
+}
@@ -0,0 +1,76 @@
+package merrmid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultRequestIDHeader is the default HTTP header carrying request IDs
+	DefaultRequestIDHeader = "X-Request-ID"
+	// DefaultRequestIDMetadataKey is the default gRPC metadata key carrying request IDs
+	DefaultRequestIDMetadataKey = "x-request-id"
+)
+
+// maxRequestIDLength bounds the length of request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// httpRequestID returns the ID of an HTTP request, taken from the request
+// header or from the response header set by an earlier middleware, or
+// generated if both are missing. The ID is set on the response header, so
+// later calls return the same ID.
+func httpRequestID(r *http.Request, h http.Header, header string, generate func() string) string {
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	if id := h.Get(header); validRequestID(id) {
+		return id
+	}
+	id := r.Header.Get(header)
+	if !validRequestID(id) {
+		id = generateRequestID(generate)
+	}
+	h.Set(header, id)
+	return id
+}
+
+// grpcRequestID returns the request ID of the incoming metadata, or a generated one
+func grpcRequestID(ctx context.Context, key string, generate func() string) string {
+	if key == "" {
+		key = DefaultRequestIDMetadataKey
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(key); len(ids) > 0 && validRequestID(ids[0]) {
+			return ids[0]
+		}
+	}
+	return generateRequestID(generate)
+}
+
+// generateRequestID calls generate, defaulting to random 128-bit hex IDs
+func generateRequestID(generate func() string) string {
+	if generate != nil {
+		return generate()
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID reports whether a client-supplied ID is safe to echo in
+// responses and logs: non-empty, bounded and made of visible ASCII characters
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package merrmid
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/mandacode-com/merr"
+)
+
+// FromValidationErrors converts validator.ValidationErrors found in err's chain,
+// such as those returned by c.ShouldBind, into a *merr.ValidationError.
+// Violations report the path of the field from the validated struct, such as
+// "address.city", named by the validator's tag name function (see JSONTagName).
+// Errors that already carry a public error, such as validation errors wrapped
+// with merr.WrapCode, and other errors are returned unchanged.
+func FromValidationErrors(err error) error {
+	if _, ok := merr.AsPublic(err); ok {
+		return err
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	v := &merr.ValidationError{}
+	for _, fe := range verrs {
+		field := fieldPath(fe)
+		v.Add(field, fe.Tag(), violationMessage(field, fe))
+	}
+	return v.Err()
+}
+
+// JSONTagName names struct fields after their json tag, falling back to the
+// Go field name. Register it with validator.Validate.RegisterTagNameFunc so
+// that violations report the keys clients send, or call RegisterJSONTagName
+// for gin's default validator.
+func JSONTagName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// RegisterJSONTagName makes gin's default validator name fields after their
+// json tag, so that binding violations report the keys clients send. It
+// changes FieldError.Field and FieldError.Namespace for every user of
+// binding.Validator, so it is only done on request, typically at startup.
+func RegisterJSONTagName() {
+	registerJSONTagName()
+}
+
+// registerJSONTagName registers JSONTagName on gin's default validator once
+var registerJSONTagName = sync.OnceFunc(func() {
+	if binding.Validator == nil {
+		return
+	}
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(JSONTagName)
+	}
+})
+
+// fieldPath returns the namespace of the field without the root struct
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// violationMessage describes the rule a field failed
+func violationMessage(field string, fe validator.FieldError) string {
+	if fe.Param() != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' rule", field, fe.Tag(), fe.Param())
+	}
+	return fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag())
+}
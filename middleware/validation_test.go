@@ -0,0 +1,51 @@
+package merrmid
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mandacode-com/merr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromValidationErrors_NestedJSONPaths(t *testing.T) {
+	type address struct {
+		City string `json:"city" validate:"required"`
+	}
+	type order struct {
+		Address address `json:"shipping_address"`
+		Items   []struct {
+			SKU string `json:"sku,omitempty" validate:"required"`
+		} `json:"items" validate:"dive"`
+		Note string `validate:"max=3"`
+	}
+
+	v := validator.New()
+	v.RegisterTagNameFunc(JSONTagName)
+	o := order{Note: "too long"}
+	o.Items = append(o.Items, struct {
+		SKU string `json:"sku,omitempty" validate:"required"`
+	}{})
+
+	err := FromValidationErrors(v.Struct(o))
+
+	assert.ElementsMatch(t, []merr.Violation{
+		{Field: "shipping_address.city", Rule: "required", Message: "shipping_address.city failed on the 'required' rule"},
+		{Field: "items[0].sku", Rule: "required", Message: "items[0].sku failed on the 'required' rule"},
+		{Field: "Note", Rule: "max", Message: "Note failed on the 'max=3' rule"},
+	}, merr.Violations(err))
+}
+
+func TestFromValidationErrors_KeepsPublicErrors(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" validate:"required"`
+	}
+	verrs := validator.New().Struct(signup{})
+
+	wrapped := merr.WrapCode(verrs, merr.ErrUnprocessableEntity, "custom")
+	assert.Same(t, wrapped, FromValidationErrors(wrapped), "public errors chosen by the caller should be kept")
+
+	var v *merr.ValidationError
+	assert.True(t, errors.As(FromValidationErrors(verrs), &v))
+}
@@ -0,0 +1,130 @@
+package merr
+
+import (
+	"errors"
+	"strings"
+)
+
+// CodePolicy derives the overall code of a Multi from its public errors.
+// It is never called with an empty slice.
+type CodePolicy func(errs []PublicErr) ErrCode
+
+// FirstCode is a CodePolicy that uses the code of the first public error.
+func FirstCode(errs []PublicErr) ErrCode {
+	return errs[0].Code()
+}
+
+// MostSevereCode is a CodePolicy that prefers server errors over client
+// errors, using the first error of the most severe class. Statuses come
+// from the registry; see Mapper.MostSevereCode.
+func MostSevereCode(errs []PublicErr) ErrCode {
+	return (*Mapper)(nil).MostSevereCode()(errs)
+}
+
+// HighestStatusCode is a CodePolicy that uses the code mapping to the
+// highest HTTP status, using the first on ties. Statuses come from the
+// registry; see Mapper.HighestStatusCode.
+func HighestStatusCode(errs []PublicErr) ErrCode {
+	return (*Mapper)(nil).HighestStatusCode()(errs)
+}
+
+// Multi is an aggregate of errors that contains at least one PublicErr.
+// It follows errors.Join semantics: errors.Is and errors.As inspect every
+// wrapped error.
+type Multi struct {
+	errs   []error
+	public []PublicErr
+	policy CodePolicy
+}
+
+// Join aggregates errs, discarding nil values, using FirstCode to derive
+// the overall code. See JoinWithPolicy.
+func Join(errs ...error) error {
+	return JoinWithPolicy(FirstCode, errs...)
+}
+
+// JoinWithPolicy aggregates errs, discarding nil values.
+// It returns nil if every error is nil. If none of the errors is a
+// PublicErr, it returns the result of errors.Join; otherwise it returns a
+// *Multi whose code is derived with policy.
+func JoinWithPolicy(policy CodePolicy, errs ...error) error {
+	var nonNil []error
+	var public []PublicErr
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		nonNil = append(nonNil, e)
+		// Aggregates are flattened unless a public error, such as one
+		// added by WrapCode, overrides them
+		pe, ok := AsPublic(e)
+		if !ok {
+			continue
+		}
+		if m, ok := pe.(*Multi); ok {
+			public = append(public, m.public...)
+		} else {
+			public = append(public, pe)
+		}
+	}
+	if len(nonNil) == 0 {
+		return nil
+	}
+	if len(public) == 0 {
+		return errors.Join(nonNil...)
+	}
+	if policy == nil {
+		policy = FirstCode
+	}
+	return &Multi{
+		errs:   nonNil,
+		public: public,
+		policy: policy,
+	}
+}
+
+// Errors returns the aggregated errors.
+func (m *Multi) Errors() []error {
+	return m.errs
+}
+
+// PublicErrors returns every public error in the aggregate, flattening
+// nested aggregates.
+func (m *Multi) PublicErrors() []PublicErr {
+	return m.public
+}
+
+// Code returns the code derived by the aggregate's policy, FirstCode if it
+// has none. The zero Multi has no public errors and gives ErrUnknown.
+func (m *Multi) Code() ErrCode {
+	if len(m.public) == 0 {
+		return ErrUnknown
+	}
+	if m.policy == nil {
+		return FirstCode(m.public)
+	}
+	return m.policy(m.public)
+}
+
+// Public returns the public messages joined by "; ".
+func (m *Multi) Public() string {
+	msgs := make([]string, len(m.public))
+	for i, pe := range m.public {
+		msgs[i] = pe.Public()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Error returns the messages of all errors separated by newlines.
+func (m *Multi) Error() string {
+	msgs := make([]string, len(m.errs))
+	for i, e := range m.errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the aggregated errors.
+func (m *Multi) Unwrap() []error {
+	return m.errs
+}
@@ -0,0 +1,106 @@
+package merr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoin_Nil(t *testing.T) {
+	assert.Nil(t, Join())
+	assert.Nil(t, Join(nil, nil))
+}
+
+func TestJoin_OnlyPlainErrors(t *testing.T) {
+	err := Join(errors.New("a"), nil, errors.New("b"))
+
+	_, ok := err.(*Multi)
+	assert.False(t, ok, "Join without public errors should behave like errors.Join")
+	assert.Equal(t, "a\nb", err.Error())
+}
+
+func TestJoin_PublicErrors(t *testing.T) {
+	plain := errors.New("disk full")
+	notFound := New(ErrNotFound, "user not found", nil)
+	invalid := New(ErrInvalidInput, "invalid email", nil)
+
+	err := Join(notFound, plain, fmt.Errorf("validate: %w", invalid))
+
+	var m *Multi
+	require.True(t, errors.As(err, &m))
+	assert.Len(t, m.Errors(), 3)
+	assert.Equal(t, []PublicErr{notFound, invalid}, m.PublicErrors())
+	assert.Equal(t, ErrNotFound, m.Code())
+	assert.Equal(t, "user not found; invalid email", m.Public())
+
+	assert.True(t, errors.Is(err, plain))
+	assert.True(t, errors.Is(err, ErrInvalidInput), "errors.Is should inspect every member")
+	assert.True(t, CheckCode(err, ErrNotFound))
+}
+
+func TestJoin_Flattens(t *testing.T) {
+	a := New(ErrNotFound, "a", nil)
+	b := New(ErrConflict, "b", nil)
+	c := New(ErrInvalidInput, "c", nil)
+
+	err := Join(Join(a, b), c)
+
+	m := err.(*Multi)
+	assert.Equal(t, []PublicErr{a, b, c}, m.PublicErrors())
+
+	m = Join(fmt.Errorf("batch: %w", Join(a, b)), c).(*Multi)
+	assert.Equal(t, []PublicErr{a, b, c}, m.PublicErrors(), "wrapped aggregates should be flattened")
+}
+
+func TestJoin_WrapCodeOverridesAggregate(t *testing.T) {
+	a := New(ErrNotFound, "a", nil)
+	b := New(ErrInvalidInput, "b", nil)
+	c := New(ErrInvalidInput, "c", nil)
+	conflict := WrapCode(Join(a, b), ErrConflict, "conflict")
+
+	m := Join(conflict, c).(*Multi)
+
+	assert.Equal(t, []PublicErr{conflict, c}, m.PublicErrors())
+	assert.Equal(t, ErrConflict, m.Code())
+	assert.Equal(t, "conflict; c", m.Public())
+}
+
+func TestCodePolicies(t *testing.T) {
+	errs := []error{
+		New(ErrInvalidInput, "invalid", nil),
+		New(ErrServiceUnavailable, "unavailable", nil),
+		New(ErrNotImplemented, "not implemented", nil),
+		New(ErrInternalServerError, "internal", nil),
+	}
+
+	tests := []struct {
+		name   string
+		policy CodePolicy
+		want   ErrCode
+	}{
+		{"first", FirstCode, ErrInvalidInput},
+		{"most severe", MostSevereCode, ErrServiceUnavailable},
+		{"highest status", HighestStatusCode, ErrServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := JoinWithPolicy(tt.policy, errs...)
+			assert.True(t, CheckCode(err, tt.want))
+		})
+	}
+
+	err := JoinWithPolicy(HighestStatusCode, New(ErrNotFound, "a", nil), New(ErrConflict, "b", nil))
+	assert.True(t, CheckCode(err, ErrConflict), "409 should beat 404")
+	err = JoinWithPolicy(MostSevereCode, New(ErrNotFound, "a", nil), New(ErrConflict, "b", nil))
+	assert.True(t, CheckCode(err, ErrNotFound), "same class should keep the first")
+}
+
+func TestMulti_ZeroValue(t *testing.T) {
+	var m Multi
+	assert.Equal(t, ErrUnknown, m.Code())
+	assert.Empty(t, m.Public())
+	assert.Empty(t, m.Error())
+}
@@ -0,0 +1,170 @@
+package merr
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// CodeSpec describes how an error code is mapped and presented.
+type CodeSpec struct {
+	// Parent is the code this code refines, e.g. ErrNotFound for "order_not_found".
+	// It must already be registered.
+	Parent ErrCode
+	// HTTPStatus is the HTTP status of the code
+	// (default: the parent's status, or 500 without a parent).
+	HTTPStatus int
+	// GRPCCode is the gRPC code of the code
+	// (default: the parent's code, or codes.Unknown without a parent).
+	GRPCCode codes.Code
+	// Title is a short, human-readable summary of the code.
+	Title string
+	// Retryable reports whether a client may retry a request failing with the code
+	// (default: the parent's behaviour, or not retryable without a parent).
+	Retryable Retryability
+	// Description documents when the code is used.
+	Description string
+}
+
+// Retryability tells whether requests failing with a code may be retried.
+// The zero value inherits the behaviour of the parent code, so that a child
+// of a retryable code can opt out with RetryDenied.
+type Retryability uint8
+
+const (
+	// RetryInherit uses the parent's behaviour.
+	RetryInherit Retryability = iota
+	// RetryAllowed marks the code as retryable.
+	RetryAllowed
+	// RetryDenied marks the code as not retryable.
+	RetryDenied
+)
+
+var registry = struct {
+	sync.RWMutex
+	specs map[ErrCode]CodeSpec
+}{
+	specs: make(map[ErrCode]CodeSpec),
+}
+
+func init() {
+	for code, status := range httpErrorMap {
+		registry.specs[code] = CodeSpec{
+			HTTPStatus: status,
+			GRPCCode:   grpcErrorMap[code],
+			Title:      defaultTitle(code),
+			Retryable:  retryability(retryableCodes[code]),
+		}
+	}
+}
+
+// Register adds a code to the registry so that ToHTTPStatus, ToGRPCCode and
+// the middlewares know how to map it. A code with a parent matches the
+// parent in CheckCode and errors.Is, and inherits its mappings unless
+// overridden. It is safe for concurrent use, but
+// codes are typically registered from init functions.
+// Register fails if the code is empty or already registered, including the
+// built-in codes.
+func Register(code ErrCode, spec CodeSpec) error {
+	if code == "" {
+		return fmt.Errorf("merr: cannot register an empty code")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, exists := registry.specs[code]; exists {
+		return fmt.Errorf("merr: code %q is already registered", code)
+	}
+	// Requiring registered parents also rules out cycles
+	if _, exists := registry.specs[spec.Parent]; spec.Parent != "" && !exists {
+		return fmt.Errorf("merr: parent %q of code %q is not registered", spec.Parent, code)
+	}
+	if spec.Title == "" {
+		spec.Title = defaultTitle(code)
+	}
+	registry.specs[code] = spec
+	return nil
+}
+
+// MustRegister is like Register but panics on failure.
+func MustRegister(code ErrCode, spec CodeSpec) {
+	if err := Register(code, spec); err != nil {
+		panic(err)
+	}
+}
+
+// unregister removes a code from the registry. It lets tests register
+// codes without leaking them into later runs.
+func unregister(code ErrCode) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.specs, code)
+}
+
+// Lookup returns the spec registered for a code.
+func Lookup(code ErrCode) (CodeSpec, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	spec, exists := registry.specs[code]
+	return spec, exists
+}
+
+// Parent returns the registered parent of the code, or "" if it has none.
+func (e ErrCode) Parent() ErrCode {
+	spec, _ := Lookup(e)
+	return spec.Parent
+}
+
+// IsA reports whether the code equals ancestor or descends from it.
+func (e ErrCode) IsA(ancestor ErrCode) bool {
+	for c := e; c != ""; c = c.Parent() {
+		if c == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Retryable reports whether a request failing with the code may be retried,
+// as set by the closest of the code and its ancestors that does not inherit
+// its retryability.
+func (e ErrCode) Retryable() bool {
+	for c := e; c != ""; c = c.Parent() {
+		if spec, _ := Lookup(c); spec.Retryable != RetryInherit {
+			return spec.Retryable == RetryAllowed
+		}
+	}
+	return false
+}
+
+// retryability converts a flag to RetryAllowed or RetryDenied.
+func retryability(retryable bool) Retryability {
+	if retryable {
+		return RetryAllowed
+	}
+	return RetryDenied
+}
+
+// Title returns the registered title of the code, or a title derived from
+// the code itself if it is not registered.
+func (e ErrCode) Title() string {
+	if spec, exists := Lookup(e); exists {
+		return spec.Title
+	}
+	return defaultTitle(e)
+}
+
+// defaultTitle turns a code such as "not_found" into "Not found".
+func defaultTitle(code ErrCode) string {
+	title := strings.ReplaceAll(string(code), "_", " ")
+	if title == "" {
+		return http.StatusText(http.StatusInternalServerError)
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
@@ -0,0 +1,136 @@
+package merr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRegister_CustomCode(t *testing.T) {
+	const codePaymentDeclined ErrCode = "payment_declined"
+	t.Cleanup(func() { unregister(codePaymentDeclined) })
+
+	require.NoError(t, Register(codePaymentDeclined, CodeSpec{
+		HTTPStatus: http.StatusPaymentRequired,
+		GRPCCode:   codes.FailedPrecondition,
+		Title:      "Payment declined",
+	}))
+
+	assert.Equal(t, http.StatusPaymentRequired, codePaymentDeclined.ToHTTPStatus())
+	assert.Equal(t, codes.FailedPrecondition, codePaymentDeclined.ToGRPCCode())
+	assert.Equal(t, "Payment declined", codePaymentDeclined.Title())
+
+	spec, ok := Lookup(codePaymentDeclined)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusPaymentRequired, spec.HTTPStatus)
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	const codeDuplicate ErrCode = "registry_test_duplicate"
+	t.Cleanup(func() { unregister(codeDuplicate) })
+
+	require.NoError(t, Register(codeDuplicate, CodeSpec{HTTPStatus: http.StatusTeapot}))
+	assert.Error(t, Register(codeDuplicate, CodeSpec{HTTPStatus: http.StatusConflict}), "registering a code twice should fail")
+	assert.Equal(t, http.StatusTeapot, codeDuplicate.ToHTTPStatus(), "the first registration should be kept")
+
+	assert.Error(t, Register(ErrNotFound, CodeSpec{HTTPStatus: http.StatusGone}), "built-in codes cannot be re-registered")
+	assert.Error(t, Register("", CodeSpec{}), "empty codes cannot be registered")
+	assert.Panics(t, func() { MustRegister(ErrNotFound, CodeSpec{}) })
+}
+
+func TestRegister_Defaults(t *testing.T) {
+	const codeDefaults ErrCode = "registry_test_defaults"
+	t.Cleanup(func() { unregister(codeDefaults) })
+
+	MustRegister(codeDefaults, CodeSpec{})
+
+	assert.Equal(t, http.StatusInternalServerError, codeDefaults.ToHTTPStatus())
+	assert.Equal(t, codes.Unknown, codeDefaults.ToGRPCCode())
+	assert.Equal(t, "Registry test defaults", codeDefaults.Title())
+}
+
+func TestUnregisteredCode(t *testing.T) {
+	assert.Equal(t, http.StatusInternalServerError, codeRoot.ToHTTPStatus())
+	assert.Equal(t, codes.Unknown, codeRoot.ToGRPCCode())
+
+	_, ok := Lookup(codeRoot)
+	assert.False(t, ok)
+}
+
+func TestBuiltinTitles(t *testing.T) {
+	assert.Equal(t, "Not found", ErrNotFound.Title())
+	assert.Equal(t, "Internal server error", ErrInternalServerError.Title())
+}
+
+func TestRegister_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		code := ErrCode("registry_test_concurrent_" + string(rune('a'+i)))
+		t.Cleanup(func() { unregister(code) })
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = Register(code, CodeSpec{HTTPStatus: http.StatusConflict})
+		}()
+		go func() {
+			defer wg.Done()
+			_ = ErrNotFound.ToHTTPStatus()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, http.StatusConflict, ErrCode("registry_test_concurrent_a").ToHTTPStatus())
+}
+
+func TestHierarchy(t *testing.T) {
+	const (
+		codeOrderNotFound ErrCode = "order_not_found"
+		codeOrderArchived ErrCode = "order_archived"
+		codeTokenExpired  ErrCode = "token_expired"
+	)
+	t.Cleanup(func() {
+		unregister(codeOrderArchived)
+		unregister(codeOrderNotFound)
+		unregister(codeTokenExpired)
+	})
+	MustRegister(codeOrderNotFound, CodeSpec{Parent: ErrNotFound})
+	MustRegister(codeOrderArchived, CodeSpec{Parent: codeOrderNotFound, HTTPStatus: http.StatusGone})
+	MustRegister(codeTokenExpired, CodeSpec{Parent: ErrUnauthorized, GRPCCode: codes.PermissionDenied})
+
+	assert.Equal(t, ErrNotFound, codeOrderNotFound.Parent())
+	assert.True(t, codeOrderArchived.IsA(ErrNotFound))
+	assert.True(t, codeOrderArchived.IsA(codeOrderArchived))
+	assert.False(t, ErrNotFound.IsA(codeOrderNotFound))
+
+	// Mappings are inherited unless overridden
+	assert.Equal(t, http.StatusNotFound, codeOrderNotFound.ToHTTPStatus())
+	assert.Equal(t, codes.NotFound, codeOrderNotFound.ToGRPCCode())
+	assert.Equal(t, http.StatusGone, codeOrderArchived.ToHTTPStatus())
+	assert.Equal(t, codes.NotFound, codeOrderArchived.ToGRPCCode())
+	assert.Equal(t, http.StatusUnauthorized, codeTokenExpired.ToHTTPStatus())
+	assert.Equal(t, codes.PermissionDenied, codeTokenExpired.ToGRPCCode())
+
+	// Matching includes ancestors
+	err := fmt.Errorf("load: %w", New(codeOrderArchived, "order archived", nil))
+	assert.True(t, CheckCode(err, ErrNotFound))
+	assert.True(t, CheckCode(err, codeOrderNotFound))
+	assert.False(t, CheckCode(err, ErrUnauthorized))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, errors.Is(New(ErrNotFound, "not found", nil), codeOrderNotFound), "parents do not match children")
+
+	// Mapper overrides of a parent apply to its descendants
+	m := NewMapper().WithHTTPStatus(ErrNotFound, http.StatusNoContent)
+	assert.Equal(t, http.StatusNoContent, m.ToHTTPStatus(codeOrderNotFound))
+	assert.Equal(t, http.StatusGone, m.ToHTTPStatus(codeOrderArchived), "the child's own mapping wins over a parent override")
+}
+
+func TestHierarchy_UnregisteredParent(t *testing.T) {
+	assert.Error(t, Register("registry_test_orphan", CodeSpec{Parent: "registry_test_missing"}))
+}
@@ -0,0 +1,130 @@
+// Package retry retries operations failing with retryable merr errors.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mandacode-com/merr"
+)
+
+// Clock provides the time to Do. It can be replaced in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for d to elapse and then sends the current time.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Policy configures how Do retries an operation
+type Policy struct {
+	// MaxAttempts is the maximum number of calls, including the first (default: 3)
+	MaxAttempts int
+	// MaxElapsed stops retrying once the next attempt would start later than
+	// MaxElapsed after the first (default: no limit)
+	MaxElapsed time.Duration
+	// InitialDelay is the delay before the second attempt (default: 100ms)
+	InitialDelay time.Duration
+	// MaxDelay caps the backoff delay (default: 10s)
+	MaxDelay time.Duration
+	// Multiplier scales the delay after each attempt (default: 2)
+	Multiplier float64
+	// Jitter randomly shortens each delay by up to this fraction, between 0 and 1 (default: 0)
+	Jitter float64
+	// Retryable reports whether an error may be retried (default: merr.IsRetryable)
+	Retryable func(err error) bool
+	// Clock provides the time (default: the system clock)
+	Clock Clock
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable,
+// or the policy is exhausted. Between attempts it waits for the retry hint
+// carried by the error (see merr.RetryAfter) or else an exponential backoff.
+// Do stops waiting when ctx is done.
+//
+// On failure Do returns every attempt's error, wrapped with its attempt
+// number, joined with merr.JoinWithPolicy so that the overall code is the
+// code of the last attempt. A context error that interrupted the wait is
+// joined last. A nil policy uses the defaults.
+func Do(ctx context.Context, fn func(ctx context.Context) error, policy *Policy) error {
+	p := withDefaults(policy)
+
+	start := p.Clock.Now()
+	delay := p.InitialDelay
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, merr.Wrap(err, fmt.Sprintf("attempt %d", attempt)))
+
+		if attempt >= p.MaxAttempts || !p.Retryable(err) {
+			break
+		}
+		wait, ok := merr.RetryAfter(err)
+		if !ok {
+			wait = p.jitter(delay)
+		}
+		if p.MaxElapsed > 0 && p.Clock.Now().Add(wait).Sub(start) > p.MaxElapsed {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			errs = append(errs, ctx.Err())
+			return merr.JoinWithPolicy(lastCode, errs...)
+		case <-p.Clock.After(wait):
+		}
+		delay = min(time.Duration(float64(delay)*p.Multiplier), p.MaxDelay)
+	}
+	return merr.JoinWithPolicy(lastCode, errs...)
+}
+
+// withDefaults returns a copy of policy with unset fields defaulted
+func withDefaults(policy *Policy) Policy {
+	var p Policy
+	if policy != nil {
+		p = *policy
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 100 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 10 * time.Second
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = 2
+	}
+	if p.Retryable == nil {
+		p.Retryable = merr.IsRetryable
+	}
+	if p.Clock == nil {
+		p.Clock = systemClock{}
+	}
+	return p
+}
+
+// jitter shortens d by a random fraction of up to p.Jitter
+func (p Policy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	return d - time.Duration(rand.Float64()*min(p.Jitter, 1)*float64(d))
+}
+
+// lastCode is a merr.CodePolicy using the code of the last public error
+func lastCode(errs []merr.PublicErr) merr.ErrCode {
+	return errs[len(errs)-1].Code()
+}
+
+// systemClock is the Clock of the system time
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
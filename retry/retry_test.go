@@ -0,0 +1,158 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mandacode-com/merr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances instantly and records every wait
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// failing returns an operation failing with errs in turn, then succeeding
+func failing(calls *int, errs ...error) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func unavailable() error {
+	return merr.New(merr.ErrServiceUnavailable, "Service unavailable", nil)
+}
+
+func TestDo_SucceedsAfterRetries(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+
+	err := Do(context.Background(), failing(&calls, unavailable(), unavailable()), &Policy{
+		MaxAttempts: 5,
+		Clock:       clock,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.waits)
+}
+
+func TestDo_ExhaustsAttempts(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	last := merr.New(merr.ErrTooManyRequests, "Slow down", nil)
+
+	err := Do(context.Background(), failing(&calls, unavailable(), unavailable(), last), &Policy{
+		InitialDelay: time.Second,
+		MaxDelay:     1500 * time.Millisecond,
+		Clock:        clock,
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Second, 1500 * time.Millisecond}, clock.waits, "delays should be capped")
+
+	var multi *merr.Multi
+	require.ErrorAs(t, err, &multi)
+	assert.Len(t, multi.Errors(), 3)
+	assert.Equal(t, merr.ErrTooManyRequests, multi.Code(), "the last attempt should decide the code")
+	assert.Contains(t, err.Error(), "attempt 3: Slow down")
+	assert.ErrorIs(t, err, last)
+}
+
+func TestDo_StopsOnNonRetryableError(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	notFound := merr.New(merr.ErrNotFound, "Order not found", nil)
+
+	err := Do(context.Background(), failing(&calls, unavailable(), notFound, unavailable()), &Policy{Clock: clock})
+
+	assert.Equal(t, 2, calls)
+	assert.True(t, merr.CheckCode(err, merr.ErrNotFound))
+
+	calls = 0
+	plain := errors.New("boom")
+	err = Do(context.Background(), failing(&calls, plain), &Policy{Clock: clock})
+	assert.Equal(t, 1, calls, "non-public errors are not retryable by default")
+	assert.ErrorIs(t, err, plain)
+}
+
+func TestDo_HonoursRetryAfter(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	hinted := merr.New(merr.ErrTooManyRequests, "Slow down", nil).RetryAfter(7 * time.Second)
+
+	err := Do(context.Background(), failing(&calls, hinted), &Policy{Clock: clock, Jitter: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{7 * time.Second}, clock.waits, "hints should not be jittered")
+}
+
+func TestDo_MaxElapsed(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+
+	err := Do(context.Background(), failing(&calls, unavailable(), unavailable(), unavailable()), &Policy{
+		MaxAttempts:  10,
+		InitialDelay: time.Second,
+		MaxElapsed:   2 * time.Second,
+		Clock:        clock,
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 2, calls, "the third attempt would start after 3s")
+	assert.Equal(t, []time.Duration{time.Second}, clock.waits)
+}
+
+func TestDo_Jitter(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+
+	_ = Do(context.Background(), failing(&calls, unavailable(), unavailable(), unavailable(), unavailable()), &Policy{
+		MaxAttempts:  5,
+		InitialDelay: time.Second,
+		Jitter:       0.5,
+		Clock:        clock,
+	})
+
+	require.Len(t, clock.waits, 4)
+	for i, wait := range clock.waits {
+		full := time.Second << i
+		assert.LessOrEqual(t, wait, full)
+		assert.GreaterOrEqual(t, wait, full/2)
+	}
+}
+
+func TestDo_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+
+	err := Do(ctx, func(context.Context) error {
+		calls++
+		cancel()
+		return unavailable()
+	}, &Policy{InitialDelay: time.Hour})
+
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, merr.CheckCode(err, merr.ErrServiceUnavailable))
+}
@@ -0,0 +1,69 @@
+package merr
+
+import "time"
+
+// retryableCodes lists the built-in codes whose failures are usually transient.
+var retryableCodes = map[ErrCode]bool{
+	ErrTimeout:            true,
+	ErrServiceUnavailable: true,
+	ErrTooManyRequests:    true,
+	ErrGatewayTimeout:     true,
+	ErrBadGateway:         true,
+	ErrTooEarly:           true,
+}
+
+// retryDelayer is implemented by errors carrying a retry hint.
+type retryDelayer interface {
+	RetryDelay() time.Duration
+}
+
+// RetryAfter returns the first positive retry hint set with
+// Error.RetryAfter in err's chain. Only the errors reporting the code of the
+// outermost PublicErr are searched, so a hint does not survive WrapCode to
+// another code.
+func RetryAfter(err error) (time.Duration, bool) {
+	pe, ok := AsPublic(err)
+	if !ok {
+		return 0, false
+	}
+	delay := retryHint(err, pe.Code())
+	return delay, delay > 0
+}
+
+// retryHint returns the first positive retry hint in err's chain, without
+// descending past public errors reporting a code other than code.
+func retryHint(err error, code ErrCode) time.Duration {
+	if err == nil {
+		return 0
+	}
+	if pe, ok := err.(PublicErr); ok && pe.Code() != code {
+		return 0
+	}
+	if rd, ok := err.(retryDelayer); ok && rd.RetryDelay() > 0 {
+		return rd.RetryDelay()
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return retryHint(u.Unwrap(), code)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if delay := retryHint(e, code); delay > 0 {
+				return delay
+			}
+		}
+	}
+	return 0
+}
+
+// IsRetryable reports whether the request that failed with err may be
+// retried: err's chain carries a retry hint, or the code of its outermost
+// PublicErr is retryable.
+func IsRetryable(err error) bool {
+	if _, ok := RetryAfter(err); ok {
+		return true
+	}
+	if pe, ok := AsPublic(err); ok {
+		return pe.Code().Retryable()
+	}
+	return false
+}
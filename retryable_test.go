@@ -0,0 +1,70 @@
+package merr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrCode_Retryable(t *testing.T) {
+	for code, want := range map[ErrCode]bool{
+		ErrTooManyRequests:      true,
+		ErrServiceUnavailable:   true,
+		ErrTooEarly:             true,
+		ErrGatewayTimeout:       true,
+		ErrNotFound:             false,
+		ErrInternalServerError:  false,
+		ErrCode("unregistered"): false,
+	} {
+		assert.Equal(t, want, code.Retryable(), code)
+	}
+
+	MustRegister("test_quota_exceeded", CodeSpec{Parent: ErrTooManyRequests})
+	t.Cleanup(func() { unregister("test_quota_exceeded") })
+	assert.True(t, ErrCode("test_quota_exceeded").Retryable(), "children of retryable codes should be retryable")
+
+	MustRegister("test_maintenance", CodeSpec{Parent: ErrServiceUnavailable, Retryable: RetryDenied})
+	t.Cleanup(func() { unregister("test_maintenance") })
+	MustRegister("test_maintenance_window", CodeSpec{Parent: "test_maintenance"})
+	t.Cleanup(func() { unregister("test_maintenance_window") })
+	MustRegister("test_lock_held", CodeSpec{Parent: ErrConflict, Retryable: RetryAllowed})
+	t.Cleanup(func() { unregister("test_lock_held") })
+	assert.False(t, ErrCode("test_maintenance").Retryable(), "children should be able to opt out")
+	assert.False(t, ErrCode("test_maintenance_window").Retryable(), "the closest explicit setting should win")
+	assert.True(t, ErrCode("test_lock_held").Retryable(), "children should be able to opt in")
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := New(ErrServiceUnavailable, "Service unavailable", nil).RetryAfter(30 * time.Second)
+
+	d, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, d)
+
+	d, ok = RetryAfter(fmt.Errorf("call: %w", Wrap(err, "fetch")))
+	assert.True(t, ok, "the hint should be found through wrappers")
+	assert.Equal(t, 30*time.Second, d)
+
+	_, ok = RetryAfter(WrapCode(err, ErrForbidden, "Blocked"))
+	assert.False(t, ok, "hints of errors wrapped with another code should be ignored")
+
+	_, ok = RetryAfter(New(ErrServiceUnavailable, "Service unavailable", nil))
+	assert.False(t, ok)
+	_, ok = RetryAfter(nil)
+	assert.False(t, ok)
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, IsRetryable(New(ErrTooManyRequests, "Slow down", nil)))
+	assert.True(t, IsRetryable(Wrap(New(ErrTooEarly, "Too early", nil), "replay")))
+	assert.False(t, IsRetryable(New(ErrNotFound, "Not found", nil)))
+	assert.True(t, IsRetryable(New(ErrConflict, "Locked", nil).RetryAfter(time.Second)), "a retry hint makes any error retryable")
+	assert.False(t, IsRetryable(WrapCode(New(ErrTooManyRequests, "Slow down", nil), ErrForbidden, "Blocked")))
+	assert.False(t, IsRetryable(WrapCode(New(ErrTooManyRequests, "Slow down", nil).RetryAfter(time.Second), ErrForbidden, "Blocked")),
+		"the outer code should win over the hint of the wrapped error")
+	assert.False(t, IsRetryable(errors.New("boom")))
+	assert.False(t, IsRetryable(nil))
+}
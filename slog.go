@@ -0,0 +1,64 @@
+package merr
+
+import (
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// stackLogging enables stacks in the values logged through slog.
+var stackLogging atomic.Bool
+
+// SetStackLogging enables or disables the stack trace in the slog values of
+// errors. It is disabled by default.
+func SetStackLogging(enabled bool) {
+	stackLogging.Store(enabled)
+}
+
+// LogValue implements slog.LogValuer. The error is logged as a group with
+// its code, public message, Wrap message, cause, the fields attached across
+// the chain and, if enabled with SetStackLogging, its stack trace.
+func (e *err) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", string(e.code)),
+		slog.String("public", e.public),
+	}
+	if e.msg != "" {
+		attrs = append(attrs, slog.String("msg", e.msg))
+	}
+	if e.error != nil {
+		attrs = append(attrs, slog.String("cause", e.error.Error()))
+	}
+	return slog.GroupValue(appendLogDetails(attrs, e)...)
+}
+
+// LogValue implements slog.LogValuer. See err.LogValue.
+func (w *wrapErr) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("msg", w.msg),
+		slog.String("cause", w.cause.Error()),
+	}
+	return slog.GroupValue(appendLogDetails(attrs, w)...)
+}
+
+// appendLogDetails appends the fields of e's chain and its stack trace.
+func appendLogDetails(attrs []slog.Attr, e interface {
+	error
+	StackTracer
+}) []slog.Attr {
+	if fields := Fields(e); len(fields) > 0 {
+		group := make([]any, 0, len(fields))
+		for _, f := range fields {
+			group = append(group, slog.Any(f.Key, f.Value))
+		}
+		attrs = append(attrs, slog.Group("fields", group...))
+	}
+	if stackLogging.Load() {
+		if frames := e.StackTrace(); len(frames) > 0 {
+			var b strings.Builder
+			writeFrames(&b, frames)
+			attrs = append(attrs, slog.String("stack", strings.TrimPrefix(b.String(), "\n")))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,70 @@
+package merr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logged logs err through a JSON handler and returns the "err" attribute
+func logged(t *testing.T, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	group, ok := record["err"].(map[string]any)
+	require.True(t, ok, "err should be logged as a group, got %v", record["err"])
+	return group
+}
+
+func TestLogValue(t *testing.T) {
+	err := New(ErrNotFound, "Order not found", errors.New("no rows")).With("order_id", "o-1")
+
+	assert.Equal(t, map[string]any{
+		"code":   "not_found",
+		"public": "Order not found",
+		"cause":  "no rows",
+		"fields": map[string]any{"order_id": "o-1"},
+	}, logged(t, err))
+
+	wrapped := Wrap(WithField(errors.New("db down"), "table", "orders"), "load orders")
+	assert.Equal(t, map[string]any{
+		"msg":    "load orders",
+		"cause":  "db down",
+		"fields": map[string]any{"table": "orders"},
+	}, logged(t, wrapped))
+}
+
+func TestLogValue_Stack(t *testing.T) {
+	SetStackLogging(true)
+	defer SetStackLogging(false)
+
+	group := logged(t, New(ErrNotFound, "Order not found", nil))
+	assert.Contains(t, group["stack"], "TestLogValue_Stack")
+
+	SetStackLogging(false)
+	group = logged(t, New(ErrNotFound, "Order not found", nil))
+	assert.NotContains(t, group, "stack")
+}
+
+func TestFormat_Quoted(t *testing.T) {
+	err := New(ErrNotFound, "Order \"o-1\" not found", nil)
+	assert.Equal(t, `"Order \"o-1\" not found"`, fmt.Sprintf("%q", err))
+	assert.Equal(t, `"load: boom"`, fmt.Sprintf("%q", Wrap(errors.New("boom"), "load")))
+	assert.Equal(t, `Order "o-1" not found`, fmt.Sprintf("%v", err))
+}
+
+func TestFormat_UnknownVerb(t *testing.T) {
+	var e fmt.Formatter = New(ErrNotFound, "Not found", nil).(*err)
+	assert.Equal(t, "%!d(Not found)", fmt.Sprintf("%d", e))
+	var w fmt.Formatter = Wrap(errors.New("boom"), "load").(*wrapErr)
+	assert.Equal(t, "%!x(load: boom)", fmt.Sprintf("%x", w))
+}
@@ -0,0 +1,79 @@
+package merr
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames captured for an error.
+const maxStackDepth = 32
+
+// stackDisabled is inverted so that capture is enabled by default.
+var stackDisabled atomic.Bool
+
+// SetStackCapture enables or disables stack capture for errors created by New.
+// Capture is enabled by default.
+func SetStackCapture(enabled bool) {
+	stackDisabled.Store(!enabled)
+}
+
+// StackCaptureEnabled reports whether errors created by New capture a stack trace.
+func StackCaptureEnabled() bool {
+	return !stackDisabled.Load()
+}
+
+// Frame is a single frame of a captured stack trace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// StackTracer is implemented by errors that carry a stack trace.
+type StackTracer interface {
+	StackTrace() []Frame
+}
+
+type stack []uintptr
+
+// callers captures the stack of the caller, skipping skip additional frames.
+func callers(skip int) stack {
+	if stackDisabled.Load() {
+		return nil
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	s := make(stack, n)
+	copy(s, pcs[:n])
+	return s
+}
+
+// frames resolves the program counters into frames.
+func (s stack) frames() []Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(s))
+	iter := runtime.CallersFrames(s)
+	for {
+		f, more := iter.Next()
+		frames = append(frames, Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// writeFrames writes frames in the "function\n\tfile:line" format.
+func writeFrames(w io.Writer, frames []Frame) {
+	for _, f := range frames {
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+}
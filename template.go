@@ -0,0 +1,97 @@
+package merr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxParamLength is the maximum length, in runes, of a sanitized message
+// parameter. Longer values are truncated and end with "…".
+const MaxParamLength = 64
+
+// Templated is implemented by errors whose public message was rendered
+// from a template by Newf.
+type Templated interface {
+	// MessageTemplate returns the template of the public message, or "" if it has none.
+	MessageTemplate() string
+	// MessageParams returns the parameters interpolated into the template.
+	MessageParams() map[string]any
+}
+
+// sanitizeParams returns a copy of params with every value sanitized.
+func sanitizeParams(params map[string]any) map[string]any {
+	if params == nil {
+		return nil
+	}
+	safe := make(map[string]any, len(params))
+	for k, v := range params {
+		safe[k] = sanitizeParam(v)
+	}
+	return safe
+}
+
+// sanitizeParam keeps booleans and numbers as they are and converts any
+// other value to a sanitized string.
+func sanitizeParam(v any) any {
+	switch v := v.(type) {
+	case bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case string:
+		return sanitizeString(v)
+	}
+	return sanitizeString(fmt.Sprint(v))
+}
+
+// sanitizeString removes braces, control and formatting characters from s
+// and truncates it to MaxParamLength runes.
+func sanitizeString(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		switch {
+		case r == '{' || r == '}' || r == utf8.RuneError:
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		}
+		if n == MaxParamLength {
+			return strings.TrimRightFunc(b.String(), unicode.IsSpace) + "…"
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
+
+// interpolate replaces {name} placeholders with sanitized params.
+// Unknown placeholders are left as they are.
+func interpolate(msg string, params map[string]any) string {
+	if len(params) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(msg, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(msg[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+		name := msg[start+1 : end]
+		if v, ok := params[name]; ok {
+			b.WriteString(msg[:start])
+			fmt.Fprint(&b, sanitizeParam(v))
+		} else {
+			b.WriteString(msg[:end+1])
+		}
+		msg = msg[end+1:]
+	}
+	b.WriteString(msg)
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package merr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewf(t *testing.T) {
+	cause := errors.New("no rows")
+	err := Newf(ErrNotFound, "Order {id} of {user} was not found", map[string]any{"id": 42, "user": "kim"}, cause)
+
+	assert.Equal(t, "Order 42 of kim was not found", err.Public())
+	assert.Equal(t, ErrNotFound, err.Code())
+	assert.ErrorIs(t, err, cause)
+	assert.NotEmpty(t, err.StackTrace())
+
+	tmpl, ok := err.(Templated)
+	require.True(t, ok)
+	assert.Equal(t, "Order {id} of {user} was not found", tmpl.MessageTemplate())
+	assert.Equal(t, map[string]any{"id": 42, "user": "kim"}, tmpl.MessageParams())
+
+	wrapped, _ := AsPublic(Wrap(err, "load order"))
+	assert.Equal(t, "Order {id} of {user} was not found", wrapped.(Templated).MessageTemplate(), "Wrap should keep the template")
+
+	assert.Empty(t, New(ErrNotFound, "Order not found", nil).(Templated).MessageTemplate())
+}
+
+func TestNewf_SanitizesParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"placeholders", "{secret}", "secret"},
+		{"control characters", "a\nb\r\x00c", "abc"},
+		{"bidi override", "abc\u202edef", "abcdef"},
+		{"truncated", strings.Repeat("x", MaxParamLength+10), strings.Repeat("x", MaxParamLength) + "…"},
+		{"stringer", errors.New("boom}"), "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Newf(ErrInvalidInput, "Invalid value {v} for {secret}", map[string]any{"v": tt.value}, nil)
+			assert.Equal(t, "Invalid value "+tt.want+" for {secret}", err.Public())
+			assert.Equal(t, tt.want, err.(Templated).MessageParams()["v"])
+		})
+	}
+}
+
+func TestNewf_Localize(t *testing.T) {
+	c := NewMessageCatalog()
+	c.Add("ko", map[string]string{"order.not_found": "주문 {id}을(를) 찾을 수 없습니다"})
+
+	err := Newf(ErrNotFound, "Order {id} was not found", map[string]any{"id": "o-1\n{x}"}, nil).
+		WithMessageKey("order.not_found", nil)
+
+	msg, ok := Localize(c, "ko", err)
+	require.True(t, ok, "nil params should keep the Newf parameters")
+	assert.Equal(t, "주문 o-1x을(를) 찾을 수 없습니다", msg)
+
+	msg, _ = c.Translate("ko", "order.not_found", map[string]any{"id": "{id}\t"})
+	assert.Equal(t, "주문 id을(를) 찾을 수 없습니다", msg, "translations should sanitize parameters too")
+}
@@ -0,0 +1,185 @@
+package merr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Localizable is implemented by errors whose public message can be translated.
+type Localizable interface {
+	// MessageKey returns the key of the public message, or "" if it has none.
+	MessageKey() string
+	// MessageParams returns the parameters interpolated into the message.
+	MessageParams() map[string]any
+}
+
+// Translator translates message keys into localized messages.
+type Translator interface {
+	// Translate returns the message for key in locale, with params
+	// interpolated, and whether a translation exists.
+	Translate(locale, key string, params map[string]any) (string, bool)
+}
+
+// Localize translates the public message of err into locale.
+// It returns the untranslated public message and false if err has no
+// message key or the translator has no translation.
+func Localize(t Translator, locale string, err PublicErr) (string, bool) {
+	if l, ok := err.(Localizable); ok && t != nil && l.MessageKey() != "" {
+		if msg, ok := t.Translate(locale, l.MessageKey(), l.MessageParams()); ok {
+			return msg, true
+		}
+	}
+	return err.Public(), false
+}
+
+// MessageCatalog is an in-memory Translator.
+// Messages may reference parameters as {name}.
+// Lookups for a regional locale such as "ko-KR" fall back to its base
+// language "ko". It is safe for concurrent use.
+type MessageCatalog struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewMessageCatalog creates an empty catalog.
+func NewMessageCatalog() *MessageCatalog {
+	return &MessageCatalog{
+		messages: make(map[string]map[string]string),
+	}
+}
+
+// Add adds messages for a locale, replacing existing keys.
+func (c *MessageCatalog) Add(locale string, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+	for k, v := range messages {
+		c.messages[locale][k] = v
+	}
+}
+
+// LoadJSON adds the messages of a JSON object for a locale.
+// Nested objects are flattened into dotted keys.
+func (c *MessageCatalog) LoadJSON(locale string, r io.Reader) error {
+	var tree map[string]any
+	if err := json.NewDecoder(r).Decode(&tree); err != nil {
+		return fmt.Errorf("merr: decode %s messages: %w", locale, err)
+	}
+	return c.addTree(locale, tree)
+}
+
+// LoadTOML adds the messages of a TOML document for a locale.
+// Tables are flattened into dotted keys.
+func (c *MessageCatalog) LoadTOML(locale string, r io.Reader) error {
+	var tree map[string]any
+	if err := toml.NewDecoder(r).Decode(&tree); err != nil {
+		return fmt.Errorf("merr: decode %s messages: %w", locale, err)
+	}
+	return c.addTree(locale, tree)
+}
+
+// LoadFile adds the messages of a JSON or TOML file named after its
+// locale, such as "ko.json" or "en-US.toml".
+func (c *MessageCatalog) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ext := filepath.Ext(path)
+	locale := strings.TrimSuffix(filepath.Base(path), ext)
+	switch strings.ToLower(ext) {
+	case ".json":
+		return c.LoadJSON(locale, f)
+	case ".toml":
+		return c.LoadTOML(locale, f)
+	}
+	return fmt.Errorf("merr: %s: unsupported message file format", path)
+}
+
+// Translate implements Translator.
+func (c *MessageCatalog) Translate(locale, key string, params map[string]any) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	for {
+		if msg, ok := c.messages[locale][key]; ok {
+			return interpolate(msg, params), true
+		}
+		i := strings.LastIndexByte(locale, '-')
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// Locales returns the locales of the catalog, sorted.
+func (c *MessageCatalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// addTree flattens decoded messages and adds them for a locale.
+func (c *MessageCatalog) addTree(locale string, tree map[string]any) error {
+	messages := make(map[string]string)
+	if err := flatten("", tree, messages); err != nil {
+		return fmt.Errorf("merr: %s messages: %w", locale, err)
+	}
+	c.Add(locale, messages)
+	return nil
+}
+
+func flatten(prefix string, tree map[string]any, out map[string]string) error {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			out[key] = v
+		case map[string]any:
+			if err := flatten(key, v, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q is not a string", key)
+		}
+	}
+	return nil
+}
+
+// normalizeLocale canonicalizes separators and case, e.g. "ko_kr" to "ko-KR".
+func normalizeLocale(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	for i, p := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(p)
+		} else if len(p) == 2 {
+			parts[i] = strings.ToUpper(p)
+		}
+	}
+	return strings.Join(parts, "-")
+}
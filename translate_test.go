@@ -0,0 +1,90 @@
+package merr
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageCatalog_Translate(t *testing.T) {
+	c := NewMessageCatalog()
+	c.Add("en", map[string]string{"order.not_found": "Order {id} was not found"})
+	c.Add("ko", map[string]string{"order.not_found": "주문 {id}을(를) 찾을 수 없습니다"})
+
+	msg, ok := c.Translate("ko", "order.not_found", map[string]any{"id": 42})
+	require.True(t, ok)
+	assert.Equal(t, "주문 42을(를) 찾을 수 없습니다", msg)
+
+	msg, ok = c.Translate("ko_kr", "order.not_found", map[string]any{"id": 42})
+	require.True(t, ok, "regional locales should fall back to their base language")
+	assert.Equal(t, "주문 42을(를) 찾을 수 없습니다", msg)
+
+	msg, ok = c.Translate("en", "order.not_found", nil)
+	require.True(t, ok)
+	assert.Equal(t, "Order {id} was not found", msg, "unknown placeholders should be kept")
+
+	_, ok = c.Translate("fr", "order.not_found", nil)
+	assert.False(t, ok)
+	_, ok = c.Translate("en", "missing", nil)
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"en", "ko"}, c.Locales())
+}
+
+func TestMessageCatalog_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"order": {"not_found": "Order not found"}}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ko-KR.toml"), []byte("[order]\nnot_found = \"주문을 찾을 수 없습니다\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fr.yaml"), []byte("order: x"), 0o644))
+
+	c := NewMessageCatalog()
+	require.NoError(t, c.LoadFile(filepath.Join(dir, "en.json")))
+	require.NoError(t, c.LoadFile(filepath.Join(dir, "ko-KR.toml")))
+	assert.Error(t, c.LoadFile(filepath.Join(dir, "fr.yaml")), "unsupported formats should fail")
+	assert.Error(t, c.LoadFile(filepath.Join(dir, "missing.json")))
+
+	msg, ok := c.Translate("en-GB", "order.not_found", nil)
+	require.True(t, ok)
+	assert.Equal(t, "Order not found", msg)
+
+	msg, ok = c.Translate("ko-KR", "order.not_found", nil)
+	require.True(t, ok)
+	assert.Equal(t, "주문을 찾을 수 없습니다", msg)
+
+	assert.Error(t, c.LoadJSON("en", strings.NewReader(`{"count": 3}`)), "non-string messages should fail")
+}
+
+func TestLocalize(t *testing.T) {
+	c := NewMessageCatalog()
+	c.Add("ko", map[string]string{"order.not_found": "주문 {id}을(를) 찾을 수 없습니다"})
+
+	err := New(ErrNotFound, "Order not found", nil).
+		WithMessageKey("order.not_found", map[string]any{"id": "o-1"})
+
+	msg, ok := Localize(c, "ko", err)
+	require.True(t, ok)
+	assert.Equal(t, "주문 o-1을(를) 찾을 수 없습니다", msg)
+	assert.Equal(t, "Order not found", err.Public(), "Public should stay untranslated")
+
+	msg, ok = Localize(c, "en", err)
+	assert.False(t, ok)
+	assert.Equal(t, "Order not found", msg)
+
+	wrapped, _ := AsPublic(Wrap(err, "load order"))
+	msg, ok = Localize(c, "ko", wrapped)
+	require.True(t, ok, "Wrap should keep the message key")
+	assert.Equal(t, "주문 o-1을(를) 찾을 수 없습니다", msg)
+
+	msg, ok = Localize(c, "ko", WrapCode(err, ErrPermissionDenied, "Access denied"))
+	assert.False(t, ok, "WrapCode replaces the public message and its key")
+	assert.Equal(t, "Access denied", msg)
+
+	_, ok = Localize(nil, "ko", err)
+	assert.False(t, ok)
+	_, ok = Localize(c, "ko", (&ValidationError{}).Add("f", "r", "m"))
+	assert.False(t, ok)
+}
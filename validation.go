@@ -0,0 +1,98 @@
+package merr
+
+import "strings"
+
+// Violation describes a single field that failed validation.
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects field violations.
+// The zero value is ready to use and reports ErrInvalidInput with the
+// public message "Invalid input".
+type ValidationError struct {
+	code       ErrCode
+	public     string
+	violations []Violation
+}
+
+// NewValidationError creates a ValidationError with a custom code and
+// public message, e.g. ErrUnprocessableEntity.
+func NewValidationError(code ErrCode, public string) *ValidationError {
+	return &ValidationError{
+		code:   code,
+		public: public,
+	}
+}
+
+// Add records a violation and returns the receiver for chaining.
+func (v *ValidationError) Add(field, rule, message string) *ValidationError {
+	v.violations = append(v.violations, Violation{
+		Field:   field,
+		Rule:    rule,
+		Message: message,
+	})
+	return v
+}
+
+// Violations returns the recorded violations.
+func (v *ValidationError) Violations() []Violation {
+	return v.violations
+}
+
+// Err returns v as an error, or nil if no violation was recorded.
+func (v *ValidationError) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Code returns the error code.
+func (v *ValidationError) Code() ErrCode {
+	if v.code == "" {
+		return ErrInvalidInput
+	}
+	return v.code
+}
+
+// Public returns the public message of the error.
+func (v *ValidationError) Public() string {
+	if v.public == "" {
+		return "Invalid input"
+	}
+	return v.public
+}
+
+// Error returns the violations as a single message.
+func (v *ValidationError) Error() string {
+	msgs := make([]string, len(v.violations))
+	for i, vi := range v.violations {
+		msgs[i] = vi.Field + ": " + vi.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether the error matches target by code. See err.Is.
+func (v *ValidationError) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrCode:
+		return v.Code().IsA(t)
+	case PublicErr:
+		return v.Code().IsA(t.Code())
+	}
+	return false
+}
+
+// Violations returns every violation found in err's chain.
+func Violations(err error) []Violation {
+	var violations []Violation
+	walk(err, func(e error) {
+		if v, ok := e.(*ValidationError); ok {
+			violations = append(violations, v.violations...)
+		}
+	})
+	return violations
+}
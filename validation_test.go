@@ -0,0 +1,57 @@
+package merr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError_Empty(t *testing.T) {
+	var v ValidationError
+	assert.Nil(t, v.Err(), "Err should be nil without violations")
+}
+
+func TestValidationError_Defaults(t *testing.T) {
+	v := &ValidationError{}
+	err := v.Add("email", "required", "email is required").
+		Add("age", "min", "age must be at least 18").
+		Err()
+	require.Error(t, err)
+
+	pe, ok := AsPublic(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrInvalidInput, pe.Code())
+	assert.Equal(t, "Invalid input", pe.Public())
+	assert.Equal(t, "validation failed: email: email is required; age: age must be at least 18", err.Error())
+	assert.True(t, errors.Is(err, ErrInvalidInput))
+	assert.Equal(t, []Violation{
+		{Field: "email", Rule: "required", Message: "email is required"},
+		{Field: "age", Rule: "min", Message: "age must be at least 18"},
+	}, v.Violations())
+}
+
+func TestValidationError_CustomCode(t *testing.T) {
+	err := NewValidationError(ErrUnprocessableEntity, "Order cannot be placed").
+		Add("quantity", "stock", "only 3 left").
+		Err()
+
+	assert.True(t, CheckCode(err, ErrUnprocessableEntity))
+	pe, _ := AsPublic(err)
+	assert.Equal(t, "Order cannot be placed", pe.Public())
+}
+
+func TestViolations_Chain(t *testing.T) {
+	a := (&ValidationError{}).Add("name", "required", "name is required").Err()
+	b := (&ValidationError{}).Add("email", "email", "email is invalid").Err()
+
+	err := Join(fmt.Errorf("row 1: %w", a), b)
+
+	assert.Equal(t, []Violation{
+		{Field: "name", Rule: "required", Message: "name is required"},
+		{Field: "email", Rule: "email", Message: "email is invalid"},
+	}, Violations(err))
+	assert.Empty(t, Violations(errors.New("plain")))
+}
@@ -0,0 +1,104 @@
+package merr
+
+import (
+	"fmt"
+	"io"
+)
+
+// Wrap adds internal context to err without changing how it is presented
+// publicly. If err's chain contains a PublicErr, the result is a PublicErr
+// with the same code, public message, message key and template; otherwise
+// the result is a plain wrapped error. The message only appears in Error(),
+// never in Public().
+// Wrap returns nil if err is nil.
+func Wrap(e error, msg string) error {
+	if e == nil {
+		return nil
+	}
+	if pe, ok := AsPublic(e); ok {
+		w := &err{
+			error:  e,
+			msg:    msg,
+			public: pe.Public(),
+			code:   pe.Code(),
+			stack:  callers(1),
+		}
+		if l, ok := pe.(Localizable); ok {
+			w.key = l.MessageKey()
+			w.params = l.MessageParams()
+		}
+		if t, ok := pe.(Templated); ok {
+			w.tmpl = t.MessageTemplate()
+		}
+		return w
+	}
+	return &wrapErr{
+		cause: e,
+		msg:   msg,
+		stack: callers(1),
+	}
+}
+
+// WrapCode wraps err with a new code and public message, overriding those
+// of any PublicErr already in the chain. The original error remains
+// reachable through errors.Is and errors.As.
+// WrapCode returns nil if err is nil.
+func WrapCode(e error, code ErrCode, public string) Error {
+	if e == nil {
+		return nil
+	}
+	return &err{
+		error:  e,
+		public: public,
+		code:   code,
+		stack:  callers(1),
+	}
+}
+
+// wrapErr wraps an error that is not a PublicErr to attach context to it.
+type wrapErr struct {
+	cause  error
+	msg    string
+	stack  stack
+	fields []Field
+}
+
+func (w *wrapErr) Error() string {
+	if w.msg != "" {
+		return w.msg + ": " + w.cause.Error()
+	}
+	return w.cause.Error()
+}
+
+func (w *wrapErr) Unwrap() error {
+	return w.cause
+}
+
+func (w *wrapErr) ownFields() []Field {
+	return w.fields
+}
+
+// StackTrace returns the stack captured by Wrap, if any.
+func (w *wrapErr) StackTrace() []Frame {
+	return w.stack.frames()
+}
+
+// Format implements fmt.Formatter. See err.Format.
+func (w *wrapErr) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, w.msg)
+			writeFrames(s, w.StackTrace())
+			fmt.Fprintf(s, "\ncaused by: %+v", w.cause)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, w.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", w.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, w.Error())
+	}
+}